@@ -5,14 +5,14 @@ import (
 	"TaskService/pkg/logger"
 	"context"
 	"fmt"
-	"github.com/spf13/viper"
 	"os"
-	"strings"
+	"path/filepath"
 	"syscall"
 
 	"TaskService/internal/app"
 	exit "TaskService/pkg/context"
 	"github.com/joho/godotenv"
+	"github.com/spf13/pflag"
 )
 
 // @title Task Service API
@@ -20,31 +20,149 @@ import (
 // @description API для управления задачами
 // @host localhost:3000
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT in the form "Bearer <token>"
 func init() {
 	_ = godotenv.Load()
 
-	viper.SetEnvKeyReplacer(strings.NewReplacer(`.`, `_`))
-	viper.AutomaticEnv()
+	config.Flags(pflag.CommandLine)
+}
 
-	if err := logger.Init(config.Log()); err != nil {
-		panic(err)
+func main() {
+	pflag.Usage = func() {
+		name := filepath.Base(os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags]\n       %s config print [flags]\n\nFlags:\n", name, name)
+		pflag.PrintDefaults()
+	}
+	pflag.Parse()
+
+	var err error
+
+	switch args := pflag.Args(); {
+	case len(args) == 0:
+		err = run()
+	case len(args) == 2 && args[0] == "config" && args[1] == "print":
+		err = printConfig()
+	default:
+		pflag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
 
-func main() {
-	app, err := app.New()
+// run возвращает ошибку, если сервис не смог запуститься или остановился аварийно:
+// main завершает по ней процесс с ненулевым кодом, чтобы оркестратор перезапустил его.
+func run() error {
+	cfg, err := config.Load(pflag.CommandLine)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	mode, err := app.ParseMode(cfg.App.Mode)
+	if err != nil {
+		return err
+	}
+
+	if err := logger.Init(cfg.Logger.Config()); err != nil {
+		return err
 	}
 
+	log := logger.Get()
+
+	watcher, err := config.Watch(pflag.CommandLine, cfg)
+	if err != nil {
+		return err
+	}
+
+	watcher.Subscribe(func(cfg config.Config) {
+		applyLogLevels(cfg.Logger)
+	}, "logger.level", "logger.named_levels")
+
+	// сигнал прерывает и ожидание зависимостей при старте
 	ctx, cancel := exit.WithSignal(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	app, err := app.New(ctx, mode, cfg, watcher)
+	if err != nil {
+		log.Error().Err(err).Msg("start failed")
+		return fmt.Errorf("start: %w", err)
+	}
+
+	exit.OnSignal(ctx, reloadLogLevel, syscall.SIGHUP)
+
 	if err := app.Run(ctx); err != nil {
-		fmt.Println(err)
+		log.Error().Err(err).Msg("service stopped with error")
+		return err
+	}
+
+	return nil
+}
+
+// printConfig выводит итоговые настройки со скрытыми секретами, а затем ошибки проверки.
+func printConfig() error {
+	cfg, err := config.Load(pflag.CommandLine)
+	if err != nil {
+		return err
+	}
+
+	if err := config.Print(os.Stdout, cfg); err != nil {
+		return err
+	}
+
+	return cfg.Validate()
+}
+
+// logLevelEnv переменные .env, которые перечитываются по SIGHUP. Остальное окружение
+// не меняется: его уже прочитали компоненты при запуске.
+var logLevelEnv = []string{"LOGGER_LEVEL", "LOGGER_NAMED_LEVELS"}
+
+// reloadLogLevel перечитывает настройки (уровни из .env, конфиг-файл) и применяет logger.level и logger.named_levels.
+// Уровни, выставленные через /admin/log-level, при этом сбрасываются к настройкам.
+func reloadLogLevel(os.Signal) {
+	if env, err := godotenv.Read(); err == nil {
+		for _, key := range logLevelEnv {
+			if value, ok := env[key]; ok {
+				os.Setenv(key, value)
+			}
+		}
+	}
+
+	log := logger.Get()
+
+	cfg, err := config.Load(pflag.CommandLine)
+	if err != nil {
+		log.Error().Err(err).Msg("reload log level failed")
 		return
 	}
 
-	return
+	applyLogLevels(cfg.Logger)
+}
+
+// applyLogLevels применяет logger.level и logger.named_levels при SIGHUP и изменении конфиг-файла.
+func applyLogLevels(cfg config.Logger) {
+	log := logger.Get()
+
+	if _, err := logger.ParseLevel(cfg.Level); err != nil {
+		log.Error().Err(err).Msg("reload log level failed")
+		return
+	}
+
+	if err := logger.SetNamedLevels(cfg.NamedLevels); err != nil {
+		log.Error().Err(err).Msg("reload log level failed")
+		return
+	}
+
+	logger.SetLevel(cfg.Level)
+
+	log.Warn().Str("level", logger.Level()).Interface("named", logger.NamedLevels()).Msg("log level reloaded")
 }
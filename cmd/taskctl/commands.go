@@ -0,0 +1,250 @@
+package main
+
+import (
+	"TaskService/pkg/client"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+type environment struct {
+	client *client.Client
+	output string
+	stdout io.Writer
+}
+
+func newEnvironment(fs *pflag.FlagSet, stdout io.Writer) (*environment, error) {
+	cfg, err := loadSettings(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.New(client.Config{
+		BaseURL: cfg.Server,
+		Token:   cfg.Token,
+		Tenant:  cfg.Tenant,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &environment{
+		client: c,
+		output: cfg.Output,
+		stdout: stdout,
+	}
+
+	return result, nil
+}
+
+func createFlags(fs *pflag.FlagSet) {
+	fs.String("title", "", "task title (required)")
+	fs.String("description", "", "task description")
+	fs.String("assignee", "", "assignee, \"me\" for yourself")
+}
+
+func runCreate(ctx context.Context, env *environment, fs *pflag.FlagSet) error {
+	title, _ := fs.GetString("title")
+	description, _ := fs.GetString("description")
+	assignee, _ := fs.GetString("assignee")
+
+	if title == "" {
+		return errors.New("--title is required")
+	}
+
+	if err := env.client.Create(ctx, client.CreateTaskRequest{Title: title, Description: description, Assignee: assignee}); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(env.stdout, "Task created")
+
+	return err
+}
+
+func runGet(ctx context.Context, env *environment, fs *pflag.FlagSet) error {
+	if fs.NArg() != 1 {
+		return errors.New("usage: taskctl get <id>")
+	}
+
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid task id %q", fs.Arg(0))
+	}
+
+	task, err := env.client.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if env.output == outputTable {
+		return printTasks(env.stdout, env.output, []client.Task{task})
+	}
+
+	return printValue(env.stdout, env.output, task)
+}
+
+func listFlags(fs *pflag.FlagSet) {
+	fs.String("status", "", "filter by status")
+	fs.String("assignee", "", "filter by assignee, \"me\" for your tasks")
+	fs.Int("limit", 0, "maximum number of tasks, all when 0")
+	fs.Int("offset", 0, "number of tasks to skip")
+}
+
+func runList(ctx context.Context, env *environment, fs *pflag.FlagSet) error {
+	status, _ := fs.GetString("status")
+	assignee, _ := fs.GetString("assignee")
+	limit, _ := fs.GetInt("limit")
+	offset, _ := fs.GetInt("offset")
+
+	var tasks []client.Task
+
+	if limit > 0 {
+		list, err := env.client.List(ctx, client.ListOptions{Status: status, Assignee: assignee, Limit: limit, Offset: offset})
+		if err != nil {
+			return err
+		}
+		tasks = list.Tasks
+	} else {
+		var err error
+		if tasks, err = collect(ctx, env, client.ListOptions{Status: status, Assignee: assignee, Offset: offset}); err != nil {
+			return err
+		}
+	}
+
+	return printTasks(env.stdout, env.output, tasks)
+}
+
+func updateFlags(fs *pflag.FlagSet) {
+	fs.Int("id", 0, "task ID (required)")
+	fs.String("title", "", "new title")
+	fs.String("description", "", "new description")
+	fs.String("status", "", "new status: created or done")
+	fs.String("assignee", "", "new assignee, \"me\" for yourself")
+}
+
+// runUpdate меняет только переданные поля: остальные берутся из текущей версии задачи.
+func runUpdate(ctx context.Context, env *environment, fs *pflag.FlagSet) error {
+	id, _ := fs.GetInt("id")
+	if id == 0 {
+		return errors.New("--id is required")
+	}
+
+	task, err := env.client.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	req := client.UpdateTaskRequest{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+	}
+
+	if fs.Changed("title") {
+		req.Title, _ = fs.GetString("title")
+	}
+	if fs.Changed("description") {
+		req.Description, _ = fs.GetString("description")
+	}
+	if fs.Changed("status") {
+		req.Status, _ = fs.GetString("status")
+	}
+	if fs.Changed("assignee") {
+		assignee, _ := fs.GetString("assignee")
+		req.Assignee = &assignee
+	}
+
+	if err := env.client.Update(ctx, req); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(env.stdout, "Task updated")
+
+	return err
+}
+
+func watchFlags(fs *pflag.FlagSet) {
+	fs.IntSlice("id", nil, "watch only these task IDs")
+	fs.String("status", "", "watch only tasks with this status")
+	fs.String("last-event-id", "", "resume after this event ID")
+}
+
+func runWatch(ctx context.Context, env *environment, fs *pflag.FlagSet) error {
+	ids, _ := fs.GetIntSlice("id")
+	status, _ := fs.GetString("status")
+	lastEventID, _ := fs.GetString("last-event-id")
+
+	if env.output == outputTable {
+		fmt.Fprintln(env.stdout, "EVENT\tTIME\tTYPE\tID\tSTATUS\tTITLE")
+	}
+
+	for e, err := range env.client.Watch(ctx, client.WatchOptions{TaskIDs: ids, Status: status, LastEventID: lastEventID}) {
+		if err != nil {
+			return err
+		}
+
+		if err := printEvent(env.stdout, env.output, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exportFlags(fs *pflag.FlagSet) {
+	fs.String("status", "", "export only tasks with this status")
+	fs.String("file", "", "write to file instead of stdout")
+}
+
+func runExport(ctx context.Context, env *environment, fs *pflag.FlagSet) (err error) {
+	status, _ := fs.GetString("status")
+	file, _ := fs.GetString("file")
+
+	format := env.output
+	if format == outputTable {
+		format = outputJSON
+	}
+
+	tasks, err := collect(ctx, env, client.ListOptions{Status: status})
+	if err != nil {
+		return err
+	}
+
+	w := env.stdout
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+
+		// ошибка Close может означать, что файл записан не полностью
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+
+		w = f
+	}
+
+	return printValue(w, format, tasks)
+}
+
+func collect(ctx context.Context, env *environment, opts client.ListOptions) ([]client.Task, error) {
+	tasks := make([]client.Task, 0)
+
+	for task, err := range env.client.ListAll(ctx, opts) {
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
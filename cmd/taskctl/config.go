@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultServer     = "http://localhost:3000"
+	defaultConfigName = ".taskctl.yaml"
+	envPrefix         = "TASKCTL"
+)
+
+type settings struct {
+	Server string
+	Token  string
+	Tenant string
+	Output string
+}
+
+// addGlobalFlags регистрирует флаги, общие для всех команд.
+func addGlobalFlags(fs *pflag.FlagSet) {
+	fs.String("server", "", "Task service URL (env TASKCTL_SERVER)")
+	fs.String("token", "", "Bearer token (env TASKCTL_TOKEN)")
+	fs.String("tenant", "", "tenant ID sent as X-Tenant-ID (env TASKCTL_TENANT)")
+	fs.String("config", "", "config file (default $HOME/"+defaultConfigName+")")
+	fs.StringP("output", "o", outputTable, "output format: table, json or yaml")
+}
+
+// loadSettings собирает настройки с приоритетом: флаги, переменные окружения, конфиг-файл.
+func loadSettings(fs *pflag.FlagSet) (settings, error) {
+	v := viper.New()
+	v.SetDefault("server", defaultServer)
+	v.SetDefault("output", outputTable)
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	for _, name := range []string{"server", "token", "tenant", "output"} {
+		if err := v.BindPFlag(name, fs.Lookup(name)); err != nil {
+			return settings{}, err
+		}
+	}
+
+	path, _ := fs.GetString("config")
+	if path == "" {
+		path = os.Getenv(envPrefix + "_CONFIG")
+	}
+
+	explicit := path != ""
+	if !explicit {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, defaultConfigName)
+		}
+	}
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			var pathErr *os.PathError
+			if explicit || !errors.As(err, &pathErr) {
+				return settings{}, fmt.Errorf("failed to read config %s: %w", path, err)
+			}
+		}
+	}
+
+	result := settings{
+		Server: v.GetString("server"),
+		Token:  v.GetString("token"),
+		Tenant: v.GetString("tenant"),
+		Output: v.GetString("output"),
+	}
+
+	switch result.Output {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return result, fmt.Errorf("unknown output format %q", result.Output)
+	}
+
+	return result, nil
+}
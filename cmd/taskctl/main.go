@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/pflag"
+)
+
+const usage = `taskctl - command-line client for the Task Service API
+
+Usage:
+  taskctl <command> [flags]
+
+Commands:
+  create   create a task
+  get      show a task by ID
+  list     list tasks
+  update   update a task
+  watch    stream task events
+  export   export all tasks as JSON or YAML
+
+Global flags:
+  --server   Task service URL (env TASKCTL_SERVER, default ` + defaultServer + `)
+  --token    bearer token (env TASKCTL_TOKEN)
+  --tenant   tenant ID (env TASKCTL_TENANT)
+  --config   config file with server and token keys (default $HOME/` + defaultConfigName + `)
+  -o, --output  table, json or yaml
+
+Run "taskctl <command> --help" for command flags.
+`
+
+type command struct {
+	flags func(fs *pflag.FlagSet)
+	run   func(ctx context.Context, env *environment, fs *pflag.FlagSet) error
+}
+
+var commands = map[string]command{
+	"create": {flags: createFlags, run: runCreate},
+	"get":    {flags: func(*pflag.FlagSet) {}, run: runGet},
+	"list":   {flags: listFlags, run: runList},
+	"update": {flags: updateFlags, run: runUpdate},
+	"watch":  {flags: watchFlags, run: runWatch},
+	"export": {flags: exportFlags, run: runExport},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		_, err := fmt.Fprint(stdout, usage)
+		return err
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	fs := pflag.NewFlagSet("taskctl "+args[0], pflag.ContinueOnError)
+	addGlobalFlags(fs)
+	cmd.flags(fs)
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	env, err := newEnvironment(fs, stdout)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	return cmd.run(ctx, env, fs)
+}
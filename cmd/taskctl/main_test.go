@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"TaskService/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeAPI минимальная реализация /tasks для проверки команд.
+type fakeAPI struct {
+	mu      sync.Mutex
+	tasks   []client.Task
+	updates []client.UpdateTaskRequest
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, string) {
+	t.Helper()
+
+	api := &fakeAPI{
+		tasks: []client.Task{
+			{ID: 1, Title: "Write report", Description: "Q3", Status: "created", Assignee: "alice"},
+			{ID: 2, Title: "Review", Description: "PR 42", Status: "done", Assignee: "bob"},
+			{ID: 3, Title: "Deploy", Status: "created"},
+		},
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		for _, task := range api.tasks {
+			if task.ID == id {
+				json.NewEncoder(w).Encode(task)
+				return
+			}
+		}
+
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	})
+
+	mux.HandleFunc("GET /tasks", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		status := r.URL.Query().Get("status")
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		list := client.TaskList{Tasks: []client.Task{}}
+		for _, task := range api.tasks {
+			if status == "" || task.Status == status {
+				list.Tasks = append(list.Tasks, task)
+			}
+		}
+
+		list.Tasks = list.Tasks[min(offset, len(list.Tasks)):]
+		if limit > 0 && limit < len(list.Tasks) {
+			list.Tasks = list.Tasks[:limit]
+		}
+
+		json.NewEncoder(w).Encode(list)
+	})
+
+	mux.HandleFunc("PUT /tasks", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		var req client.UpdateTaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		api.updates = append(api.updates, req)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	// конфиг-файл из домашнего каталога не должен влиять на тесты
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TASKCTL_SERVER", srv.URL)
+
+	return api, srv.URL
+}
+
+func TestRun_Flags(t *testing.T) {
+	newFakeAPI(t)
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "unknown command", args: []string{"delete"}, err: `unknown command "delete"`},
+		{name: "unknown flag", args: []string{"list", "--verbose"}, err: "unknown flag: --verbose"},
+		{name: "unknown output", args: []string{"list", "-o", "xml"}, err: `unknown output format "xml"`},
+		{name: "create without title", args: []string{"create"}, err: "--title is required"},
+		{name: "get without id", args: []string{"get"}, err: "usage: taskctl get <id>"},
+		{name: "get invalid id", args: []string{"get", "abc"}, err: `invalid task id "abc"`},
+		{name: "update without id", args: []string{"update", "--status", "done"}, err: "--id is required"},
+		{name: "help", args: []string{"--help"}},
+		{name: "command help", args: []string{"list", "--help"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := run(tt.args, &out)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestRun_UpdateMergesFields(t *testing.T) {
+	me := "me"
+
+	tests := []struct {
+		name string
+		args []string
+		want client.UpdateTaskRequest
+	}{
+		{
+			name: "status only",
+			args: []string{"--id", "1", "--status", "done"},
+			want: client.UpdateTaskRequest{ID: 1, Title: "Write report", Description: "Q3", Status: "done"},
+		},
+		{
+			name: "title and empty description",
+			args: []string{"--id", "1", "--title", "Final report", "--description", ""},
+			want: client.UpdateTaskRequest{ID: 1, Title: "Final report", Status: "created"},
+		},
+		{
+			name: "assignee",
+			args: []string{"--id", "2", "--assignee", "me"},
+			want: client.UpdateTaskRequest{ID: 2, Title: "Review", Description: "PR 42", Status: "done", Assignee: &me},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, _ := newFakeAPI(t)
+
+			var out bytes.Buffer
+			require.NoError(t, run(append([]string{"update"}, tt.args...), &out))
+
+			assert.Equal(t, "Task updated\n", out.String())
+			require.Len(t, api.updates, 1)
+			assert.Equal(t, tt.want, api.updates[0])
+		})
+	}
+}
+
+func TestRun_Output(t *testing.T) {
+	newFakeAPI(t)
+
+	tests := []struct {
+		name  string
+		args  []string
+		check func(t *testing.T, out []byte)
+	}{
+		{
+			name: "table",
+			args: []string{"list", "--status", "created"},
+			check: func(t *testing.T, out []byte) {
+				assert.Equal(t, "ID  STATUS   ASSIGNEE  TITLE         DESCRIPTION\n"+
+					"1   created  alice     Write report  Q3\n"+
+					"3   created            Deploy        \n", string(out))
+			},
+		},
+		{
+			name: "json",
+			args: []string{"list", "--output", "json", "--limit", "2"},
+			check: func(t *testing.T, out []byte) {
+				var tasks []client.Task
+				require.NoError(t, json.Unmarshal(out, &tasks))
+				assert.Len(t, tasks, 2)
+				assert.Equal(t, "Review", tasks[1].Title)
+			},
+		},
+		{
+			name: "get json",
+			args: []string{"get", "2", "-o", "json"},
+			check: func(t *testing.T, out []byte) {
+				var task client.Task
+				require.NoError(t, json.Unmarshal(out, &task))
+				assert.Equal(t, "bob", task.Assignee)
+			},
+		},
+		{
+			name: "get table",
+			args: []string{"get", "3"},
+			check: func(t *testing.T, out []byte) {
+				assert.Contains(t, string(out), "3   created")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, run(tt.args, &out))
+			tt.check(t, out.Bytes())
+		})
+	}
+}
+
+func TestRun_Export(t *testing.T) {
+	newFakeAPI(t)
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		args   []string
+		decode func(data []byte, v any) error
+		want   []int
+	}{
+		{name: "json by default", args: []string{}, decode: json.Unmarshal, want: []int{1, 2, 3}},
+		{name: "yaml with status", args: []string{"-o", "yaml", "--status", "done"}, decode: yaml.Unmarshal, want: []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+
+			var out bytes.Buffer
+			require.NoError(t, run(append([]string{"export", "--file", path}, tt.args...), &out))
+			assert.Empty(t, out.String())
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			var tasks []client.Task
+			require.NoError(t, tt.decode(data, &tasks))
+
+			ids := make([]int, 0, len(tasks))
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+
+	err := run([]string{"export", "--file", filepath.Join(dir, "missing", "tasks.json")}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"TaskService/pkg/client"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+func printTasks(w io.Writer, format string, tasks []client.Task) error {
+	switch format {
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSTATUS\tASSIGNEE\tTITLE\tDESCRIPTION")
+		for _, t := range tasks {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", t.ID, t.Status, t.Assignee, t.Title, t.Description)
+		}
+		return tw.Flush()
+	default:
+		return printValue(w, format, tasks)
+	}
+}
+
+func printEvent(w io.Writer, format string, e client.TaskEvent) error {
+	switch format {
+	case outputTable:
+		// у resync нет задачи
+		task := client.Task{}
+		if e.Task != nil {
+			task = *e.Task
+		}
+
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			e.ID, e.Time.Format("2006-01-02T15:04:05Z07:00"), e.Type, task.ID, task.Status, task.Title)
+		return err
+	case outputJSON:
+		return json.NewEncoder(w).Encode(e)
+	default:
+		if _, err := fmt.Fprintln(w, "---"); err != nil {
+			return err
+		}
+		return printValue(w, format, e)
+	}
+}
+
+// printValue выводит значение в JSON или YAML. Для YAML значение сначала
+// проходит через JSON, чтобы имена полей совпадали с API.
+func printValue(w io.Writer, format string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if format == outputJSON {
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	defer enc.Close()
+
+	return enc.Encode(generic)
+}
@@ -1,53 +1,355 @@
 package config
 
 import (
+	"TaskService/internal/auth"
+	"TaskService/internal/health"
+	"TaskService/internal/lifecycle"
+	"TaskService/internal/ratelimit"
+	"TaskService/internal/service/task"
 	"TaskService/pkg/kafka"
 	"TaskService/pkg/logger"
-	"fmt"
+	"TaskService/pkg/retry"
+	"TaskService/pkg/tracing"
+	"TaskService/pkg/version"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 
 	"TaskService/internal/storage/postgres"
-
-	"github.com/spf13/viper"
 )
 
-func Psql() postgres.Config {
-	result := postgres.Config{
-		URL: fmt.Sprintf("postgresql://%s:%s@%s/%s?sslmode=disable",
-			viper.GetString("postgres.user"),
-			viper.GetString("postgres.password"),
-			viper.GetString("postgres.url"),
-			viper.GetString("postgres.name"),
-		),
-		Driver: viper.GetString("postgres.driver"),
+// Config настройки сервиса. Ключ поля — тег mapstructure: он же путь в конфиг-файле (postgres.url),
+// имя флага (--postgres.url) и переменной окружения (POSTGRES_URL).
+// Поля с тегом secret:"true" config print не показывает. Секрет можно прочитать из файла,
+// путь к которому задан в парном ключе с суффиксом _file (POSTGRES_PASSWORD_FILE).
+type Config struct {
+	App          App       `mapstructure:"app"`
+	Server       Server    `mapstructure:"server"`
+	GRPC         GRPC      `mapstructure:"grpc"`
+	Postgres     Postgres  `mapstructure:"postgres"`
+	Kafka        Kafka     `mapstructure:"kafka"`
+	Logger       Logger    `mapstructure:"logger"`
+	Auth         Auth      `mapstructure:"auth"`
+	RateLimit    RateLimit `mapstructure:"ratelimit"`
+	Tenant       Tenant    `mapstructure:"tenant"`
+	Task         Task      `mapstructure:"task"`
+	Health       Health    `mapstructure:"health"`
+	Shutdown     Shutdown  `mapstructure:"shutdown"`
+	StartupRetry Retry     `mapstructure:"startup_retry"`
+	ProcessRetry Retry     `mapstructure:"process_retry"`
+	Tracing      Tracing   `mapstructure:"tracing"`
+}
+
+type App struct {
+	// Mode компоненты процесса: api, worker или all.
+	Mode string `mapstructure:"mode" flag:"mode"`
+}
+
+type Server struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+}
+
+func (s Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+type GRPC struct {
+	Port int `mapstructure:"port"`
+}
+
+func (g GRPC) Addr() string {
+	return net.JoinHostPort("", strconv.Itoa(g.Port))
+}
+
+type Postgres struct {
+	// URL адрес сервера в виде host:port.
+	URL          string `mapstructure:"url"`
+	User         string `mapstructure:"user"`
+	Password     string `mapstructure:"password" secret:"true"`
+	PasswordFile string `mapstructure:"password_file"`
+	Name         string `mapstructure:"name"`
+	Driver       string `mapstructure:"driver"`
+	SSLMode      string `mapstructure:"sslmode"`
+	// SSLRootCert сертификат CA для sslmode verify-ca и verify-full.
+	SSLRootCert string `mapstructure:"sslrootcert"`
+	// SSLCert и SSLKey клиентский сертификат и ключ, если сервер их требует.
+	SSLCert         string        `mapstructure:"sslcert"`
+	SSLKey          string        `mapstructure:"sslkey"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+}
+
+func (p Postgres) Config() postgres.Config {
+	query := url.Values{"sslmode": {p.SSLMode}}
+
+	for key, value := range map[string]string{
+		"sslrootcert": p.SSLRootCert,
+		"sslcert":     p.SSLCert,
+		"sslkey":      p.SSLKey,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
 	}
 
-	return result
+	dsn := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(p.User, p.Password),
+		Host:     p.URL,
+		Path:     p.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return postgres.Config{
+		URL:             dsn.String(),
+		Driver:          p.Driver,
+		MaxOpenConns:    p.MaxOpenConns,
+		MaxIdleConns:    p.MaxIdleConns,
+		ConnMaxLifetime: p.ConnMaxLifetime,
+		ConnMaxIdleTime: p.ConnMaxIdleTime,
+	}
+}
+
+type Kafka struct {
+	// Brokers в переменной окружения задаются через запятую: "kafka-1:9093,kafka-2:9093".
+	Brokers  []string      `mapstructure:"brokers"`
+	Topic    string        `mapstructure:"topic"`
+	ClientID string        `mapstructure:"client_id"`
+	GroupID  string        `mapstructure:"group_id"`
+	Version  string        `mapstructure:"version"`
+	SASL     KafkaSASL     `mapstructure:"sasl"`
+	TLS      KafkaTLS      `mapstructure:"tls"`
+	Producer KafkaProducer `mapstructure:"producer"`
+}
+
+type KafkaSASL struct {
+	Mechanism    string `mapstructure:"mechanism"`
+	User         string `mapstructure:"user"`
+	Password     string `mapstructure:"password" secret:"true"`
+	PasswordFile string `mapstructure:"password_file"`
+}
+
+type KafkaTLS struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"ca_file"`
+	CertFile           string `mapstructure:"cert_file"`
+	KeyFile            string `mapstructure:"key_file"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+type KafkaProducer struct {
+	Acks          string        `mapstructure:"acks"`
+	Retries       int           `mapstructure:"retries"`
+	Compression   string        `mapstructure:"compression"`
+	Idempotent    bool          `mapstructure:"idempotent"`
+	Linger        time.Duration `mapstructure:"linger"`
+	BatchBytes    int           `mapstructure:"batch_bytes"`
+	BatchMessages int           `mapstructure:"batch_messages"`
+}
+
+func (k Kafka) Config() kafka.Config {
+	return kafka.Config{
+		Brokers:  k.Brokers,
+		Topic:    k.Topic,
+		ClientID: k.ClientID,
+		GroupID:  k.GroupID,
+		Version:  k.Version,
+		SASL: kafka.SASLConfig{
+			Mechanism: k.SASL.Mechanism,
+			User:      k.SASL.User,
+			Password:  k.SASL.Password,
+		},
+		TLS: kafka.TLSConfig{
+			Enabled:            k.TLS.Enabled,
+			CAFile:             k.TLS.CAFile,
+			CertFile:           k.TLS.CertFile,
+			KeyFile:            k.TLS.KeyFile,
+			InsecureSkipVerify: k.TLS.InsecureSkipVerify,
+		},
+		Producer: kafka.ProducerConfig{
+			Acks:          k.Producer.Acks,
+			Retries:       k.Producer.Retries,
+			Compression:   k.Producer.Compression,
+			Idempotent:    k.Producer.Idempotent,
+			Linger:        k.Producer.Linger,
+			BatchBytes:    k.Producer.BatchBytes,
+			BatchMessages: k.Producer.BatchMessages,
+		},
+	}
+}
+
+type Logger struct {
+	Dir               string `mapstructure:"dir"`
+	Filename          string `mapstructure:"filename"`
+	Level             string `mapstructure:"level"`
+	MaxSizeMB         int    `mapstructure:"max_size_mb"`
+	MaxBackups        int    `mapstructure:"max_backups"`
+	MaxAgeDays        int    `mapstructure:"max_age_days"`
+	Compress          bool   `mapstructure:"compress"`
+	DuplicateToStdout bool   `mapstructure:"duplicate_to_stdout"`
+	TimeFormat        string `mapstructure:"time_format"`
+	ServiceName       string `mapstructure:"service_name"`
+	Environment       string `mapstructure:"environment"`
+	// NamedLevels в переменной окружения задаются строкой "kafka=DEBUG,postgres=WARNING".
+	NamedLevels map[string]string `mapstructure:"named_levels"`
+	// Redact в переменной окружения задается строкой "title=hash,description=mask".
+	Redact       map[string]string `mapstructure:"redact"`
+	SampleBurst  uint32            `mapstructure:"sample_burst"`
+	SamplePeriod time.Duration     `mapstructure:"sample_period"`
+	SampleEvery  uint32            `mapstructure:"sample_every"`
 }
 
-func Log() logger.Config {
+func (l Logger) Config() logger.Config {
 	return logger.Config{
-		Dir:               viper.GetString("logger.dir"),
-		Filename:          viper.GetString("logger.filename"),
-		Level:             viper.GetString("logger.level"),
-		MaxSizeMB:         viper.GetInt("logger.max_size_mb"),
-		MaxBackups:        viper.GetInt("logger.max_backups"),
-		MaxAgeDays:        viper.GetInt("logger.max_age_days"),
-		Compress:          viper.GetBool("logger.compress"),
-		DuplicateToStdout: viper.GetBool("logger.duplicate_to_stdout"),
-		TimeFormat:        viper.GetString("logger.time_format"),
-		ServiceName:       viper.GetString("logger.service_name"),
+		Dir:               l.Dir,
+		Filename:          l.Filename,
+		Level:             l.Level,
+		MaxSizeMB:         l.MaxSizeMB,
+		MaxBackups:        l.MaxBackups,
+		MaxAgeDays:        l.MaxAgeDays,
+		Compress:          l.Compress,
+		DuplicateToStdout: l.DuplicateToStdout,
+		TimeFormat:        l.TimeFormat,
+		ServiceName:       l.ServiceName,
+		Environment:       l.Environment,
+		Version:           version.Version,
+		Commit:            version.Commit,
+		NamedLevels:       l.NamedLevels,
+		Redact:            l.Redact,
+		SampleBurst:       l.SampleBurst,
+		SamplePeriod:      l.SamplePeriod,
+		SampleEvery:       l.SampleEvery,
 	}
 }
 
-func Kfk() kafka.Config {
-	result := kafka.Config{
-		Broker: viper.GetString("kafka.brokers"),
-		Topic:  viper.GetString("kafka.topic"),
+type Auth struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	JWTSecret          string        `mapstructure:"jwt_secret" secret:"true"`
+	JWTSecretFile      string        `mapstructure:"jwt_secret_file"`
+	JWKSFile           string        `mapstructure:"jwks_file"`
+	JWKSReloadInterval time.Duration `mapstructure:"jwks_reload_interval"`
+	Issuer             string        `mapstructure:"issuer"`
+	Audience           string        `mapstructure:"audience"`
+	SwaggerPublic      bool          `mapstructure:"swagger_public"`
+}
+
+func (a Auth) Config() auth.Config {
+	return auth.Config{
+		Enabled:            a.Enabled,
+		Secret:             a.JWTSecret,
+		JWKSFile:           a.JWKSFile,
+		JWKSReloadInterval: a.JWKSReloadInterval,
+		Issuer:             a.Issuer,
+		Audience:           a.Audience,
+		SwaggerPublic:      a.SwaggerPublic,
+	}
+}
+
+type RateLimit struct {
+	Enabled    bool    `mapstructure:"enabled"`
+	Backend    string  `mapstructure:"backend"`
+	ReadRate   float64 `mapstructure:"read_rate"`
+	ReadBurst  int     `mapstructure:"read_burst"`
+	WriteRate  float64 `mapstructure:"write_rate"`
+	WriteBurst int     `mapstructure:"write_burst"`
+}
+
+func (r RateLimit) Config() ratelimit.Config {
+	return ratelimit.Config{
+		Enabled: r.Enabled,
+		Backend: r.Backend,
+		Read: ratelimit.Limit{
+			Rate:  r.ReadRate,
+			Burst: r.ReadBurst,
+		},
+		Write: ratelimit.Limit{
+			Rate:  r.WriteRate,
+			Burst: r.WriteBurst,
+		},
+	}
+}
+
+type Tenant struct {
+	MaxTasks int `mapstructure:"max_tasks"`
+	// Quotas в переменной окружения задаются строкой "sales=1000,hr=200".
+	Quotas map[string]int `mapstructure:"quotas"`
+}
+
+type Task struct {
+	// ProcessTimeout ограничивает обработку одного сообщения из Kafka.
+	ProcessTimeout time.Duration `mapstructure:"process_timeout"`
+	// Workers сколько сообщений обрабатывается одновременно.
+	Workers int `mapstructure:"workers"`
+}
+
+func (c Config) TaskConfig() task.Config {
+	return task.Config{
+		MaxTasks:       c.Tenant.MaxTasks,
+		Quotas:         c.Tenant.Quotas,
+		ProcessTimeout: c.Task.ProcessTimeout,
+		Workers:        c.Task.Workers,
+		Retry:          c.ProcessRetry.Config(),
 	}
+}
 
-	return result
+type Health struct {
+	Timeout       time.Duration `mapstructure:"timeout"`
+	ShutdownDelay time.Duration `mapstructure:"shutdown_delay"`
 }
 
-func Srv() string {
-	return fmt.Sprintf(":%d", viper.GetInt("server.port"))
+func (h Health) Config() health.Config {
+	return health.Config{
+		Timeout:       h.Timeout,
+		ShutdownDelay: h.ShutdownDelay,
+	}
+}
+
+type Shutdown struct {
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+func (s Shutdown) Config() lifecycle.Config {
+	return lifecycle.Config{
+		Timeout: s.Timeout,
+	}
+}
+
+// Retry политика повторов: startup_retry — подключения к зависимостям при старте,
+// process_retry — чтения и обновления задачи при обработке сообщения.
+type Retry struct {
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	MaxInterval     time.Duration `mapstructure:"max_interval"`
+	Multiplier      float64       `mapstructure:"multiplier"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+	MaxAttempts     int           `mapstructure:"max_attempts"`
+}
+
+func (r Retry) Config() retry.Config {
+	return retry.Config{
+		InitialInterval: r.InitialInterval,
+		MaxInterval:     r.MaxInterval,
+		Multiplier:      r.Multiplier,
+		Timeout:         r.Timeout,
+		MaxAttempts:     r.MaxAttempts,
+	}
+}
+
+type Tracing struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+func (t Tracing) Config() tracing.Config {
+	return tracing.Config{
+		Enabled:     t.Enabled,
+		Endpoint:    t.Endpoint,
+		ServiceName: t.ServiceName,
+		SampleRatio: t.SampleRatio,
+	}
 }
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+postgres:
+  url: db:5432
+  user: postgres
+  password: s3cret
+  name: tasks
+kafka:
+  brokers: kafka:9092
+  topic: from-file
+logger:
+  named_levels:
+    kafka: DEBUG
+tenant:
+  quotas:
+    sales: 10
+`
+
+func load(t *testing.T, args ...string) Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	Flags(fs)
+	require.NoError(t, fs.Parse(append([]string{"--config", path}, args...)))
+
+	cfg, err := Load(fs)
+	require.NoError(t, err)
+
+	return cfg
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	t.Setenv("KAFKA_TOPIC", "from-env")
+	t.Setenv("KAFKA_BROKERS", "kafka-1:9092,kafka-2:9092")
+	t.Setenv("TENANT_QUOTAS", "hr=5, sales=20")
+
+	cfg := load(t, "--kafka.topic", "from-flag", "--mode", "worker")
+
+	assert.Equal(t, "db:5432", cfg.Postgres.URL)
+	assert.Equal(t, "from-flag", cfg.Kafka.Topic)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, "worker", cfg.App.Mode)
+	assert.Equal(t, map[string]int{"hr": 5, "sales": 20}, cfg.Tenant.Quotas)
+	assert.Equal(t, map[string]string{"kafka": "DEBUG"}, cfg.Logger.NamedLevels)
+
+	assert.Equal(t, 30*time.Second, cfg.Task.ProcessTimeout)
+	assert.Equal(t, "disable", cfg.Postgres.SSLMode)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv("LOGGER_LEVEL", "LOUD")
+	t.Setenv("POSTGRES_SSLMODE", "sometimes")
+
+	cfg := load(t)
+	cfg.Kafka.Topic = ""
+
+	err := cfg.Validate()
+
+	assert.ErrorContains(t, err, "kafka.topic (KAFKA_TOPIC): is required")
+	assert.ErrorContains(t, err, "logger.level (LOGGER_LEVEL)")
+	assert.ErrorContains(t, err, `postgres.sslmode (POSTGRES_SSLMODE): must be one of`)
+
+	cfg = load(t)
+	cfg.Postgres.SSLCert = "/nonexistent/client.pem"
+	cfg.Postgres.MaxOpenConns = 5
+	cfg.Postgres.MaxIdleConns = 10
+
+	err = cfg.Validate()
+
+	assert.ErrorContains(t, err, "postgres.sslcert (POSTGRES_SSLCERT): stat /nonexistent/client.pem")
+	assert.ErrorContains(t, err, "postgres.sslkey (POSTGRES_SSLKEY): client certificate and key must be set together")
+	assert.ErrorContains(t, err, "postgres.max_idle_conns (POSTGRES_MAX_IDLE_CONNS): must not exceed")
+}
+
+func TestPrint_MasksSecrets(t *testing.T) {
+	cfg := load(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, cfg))
+
+	assert.Contains(t, buf.String(), "password: '***'")
+	assert.Contains(t, buf.String(), `jwt_secret: ""`)
+	assert.NotContains(t, buf.String(), "s3cret")
+}
+
+func TestLoad_SecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	t.Setenv("AUTH_JWT_SECRET_FILE", path)
+
+	cfg := load(t)
+	assert.Equal(t, "from-file", cfg.Auth.JWTSecret)
+
+	t.Setenv("POSTGRES_PASSWORD_FILE", path)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	Flags(fs)
+	require.NoError(t, fs.Parse([]string{"--postgres.password", "inline"}))
+
+	_, err := Load(fs)
+	assert.ErrorContains(t, err, "postgres.password (POSTGRES_PASSWORD) and postgres.password_file (POSTGRES_PASSWORD_FILE) are both set")
+}
+
+func TestPostgres_Config(t *testing.T) {
+	p := Postgres{URL: "db:5432", User: "app", Password: "p@ss/word", Name: "tasks", SSLMode: "require"}
+
+	assert.Equal(t, "postgresql://app:p%40ss%2Fword@db:5432/tasks?sslmode=require", p.Config().URL)
+
+	p.SSLMode = "verify-full"
+	p.SSLRootCert = "/etc/ssl/ca.pem"
+	p.SSLCert = "/etc/ssl/client.pem"
+	p.SSLKey = "/etc/ssl/client.key"
+	p.MaxOpenConns = 20
+
+	cfg := p.Config()
+	assert.Equal(t, "postgresql://app:p%40ss%2Fword@db:5432/tasks?"+
+		"sslcert=%2Fetc%2Fssl%2Fclient.pem&sslkey=%2Fetc%2Fssl%2Fclient.key&sslmode=verify-full&sslrootcert=%2Fetc%2Fssl%2Fca.pem", cfg.URL)
+	assert.Equal(t, 20, cfg.MaxOpenConns)
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"TaskService/pkg/kafka"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// EnvConfigFile переменная окружения с путем к конфиг-файлу, если не задан флаг --config.
+const EnvConfigFile = "CONFIG_FILE"
+
+// defaults значения, которые раньше были зашиты в код. Остальные настройки по умолчанию
+// выставляют сами пакеты (validateConfig).
+var defaults = map[string]any{
+	"app.mode":                       "all",
+	"server.port":                    3000,
+	"grpc.port":                      50051,
+	"postgres.driver":                "postgres",
+	"postgres.sslmode":               "disable",
+	"kafka.producer.acks":            kafka.AcksAll,
+	"kafka.producer.retries":         5,
+	"task.process_timeout":           30 * time.Second,
+	"task.workers":                   10,
+	"process_retry.initial_interval": 100 * time.Millisecond,
+	"process_retry.max_interval":     2 * time.Second,
+	"process_retry.max_attempts":     3,
+}
+
+// field ключ Config: путь в конфиг-файле и индекс поля для reflect.
+type field struct {
+	key    string
+	flag   string
+	index  []int
+	typ    reflect.Type
+	secret bool
+}
+
+// fields перечисляет конечные (не структуры) поля Config.
+func fields(t reflect.Type, prefix string, index []int) []field {
+	var result []field
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		key := prefix + f.Tag.Get("mapstructure")
+		idx := append(append([]int{}, index...), i)
+
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(time.Duration(0)) {
+			result = append(result, fields(f.Type, key+".", idx)...)
+			continue
+		}
+
+		flag := f.Tag.Get("flag")
+		if flag == "" {
+			flag = key
+		}
+
+		result = append(result, field{
+			key:    key,
+			flag:   flag,
+			index:  idx,
+			typ:    f.Type,
+			secret: f.Tag.Get("secret") == "true",
+		})
+	}
+
+	return result
+}
+
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Flags регистрирует --config и по флагу на каждый ключ Config, например --kafka.topic.
+func Flags(fs *pflag.FlagSet) {
+	fs.String("config", "", "config file, YAML or TOML (env "+EnvConfigFile+")")
+
+	for _, f := range fields(reflect.TypeOf(Config{}), "", nil) {
+		usage := "env " + envName(f.key)
+
+		switch {
+		case f.typ == reflect.TypeOf(time.Duration(0)):
+			fs.Duration(f.flag, 0, usage)
+		case f.typ.Kind() == reflect.Bool:
+			fs.Bool(f.flag, false, usage)
+		case f.typ.Kind() == reflect.Int:
+			fs.Int(f.flag, 0, usage)
+		case f.typ.Kind() == reflect.Uint32:
+			fs.Uint32(f.flag, 0, usage)
+		case f.typ.Kind() == reflect.Float64:
+			fs.Float64(f.flag, 0, usage)
+		case f.typ.Kind() == reflect.Slice:
+			fs.StringSlice(f.flag, nil, usage)
+		default:
+			fs.String(f.flag, "", usage)
+		}
+	}
+}
+
+// Load собирает Config из конфиг-файла, переменных окружения и флагов fs (зарегистрированных Flags).
+// Каждый следующий источник переопределяет предыдущий. Значения не проверяются — см. Validate.
+func Load(fs *pflag.FlagSet) (Config, error) {
+	v, err := newViper(fs)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return decode(v)
+}
+
+func newViper(fs *pflag.FlagSet) (*viper.Viper, error) {
+	v := viper.New()
+
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	for _, f := range fields(reflect.TypeOf(Config{}), "", nil) {
+		if err := v.BindEnv(f.key, envName(f.key)); err != nil {
+			return nil, err
+		}
+
+		// viper берет значение флага, только если он задан явно
+		if flag := fs.Lookup(f.flag); flag != nil {
+			if err := v.BindPFlag(f.key, flag); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	path, _ := fs.GetString("config")
+	if path == "" {
+		path = os.Getenv(EnvConfigFile)
+	}
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+	}
+
+	return v, nil
+}
+
+func decode(v *viper.Viper) (Config, error) {
+	var cfg Config
+
+	err := v.Unmarshal(&cfg, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		stringToMapHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)))
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if err := readSecrets(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// readSecrets подставляет секреты из файлов, заданных парными ключами *_file, например из
+// секретов Docker и Kubernetes. Перевод строки в конце файла отбрасывается.
+func readSecrets(cfg *Config) error {
+	v := reflect.ValueOf(cfg).Elem()
+	all := fields(v.Type(), "", nil)
+
+	for _, secret := range all {
+		if !secret.secret {
+			continue
+		}
+
+		i := slices.IndexFunc(all, func(f field) bool { return f.key == secret.key+"_file" })
+		if i < 0 {
+			continue
+		}
+
+		file := all[i]
+
+		path := v.FieldByIndex(file.index).String()
+		if path == "" {
+			continue
+		}
+
+		value := v.FieldByIndex(secret.index)
+		if value.String() != "" {
+			return fmt.Errorf("%s (%s) and %s (%s) are both set, use one of them",
+				secret.key, envName(secret.key), file.key, envName(file.key))
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s (%s): %w", file.key, envName(file.key), err)
+		}
+
+		value.SetString(strings.TrimRight(string(data), "\r\n"))
+	}
+
+	return nil
+}
+
+// stringToMapHook разбирает словари, заданные строкой в переменной окружения или флаге:
+// "kafka=DEBUG,postgres=WARNING". В конфиг-файле их можно задать обычным словарем.
+func stringToMapHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Map {
+		return data, nil
+	}
+
+	result := make(map[string]string)
+
+	for _, item := range strings.Split(data.(string), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(item, "=")
+		result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return result, nil
+}
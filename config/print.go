@@ -0,0 +1,70 @@
+package config
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const maskedSecret = "***"
+
+// Print пишет cfg в w в формате конфиг-файла YAML. Значения секретов заменяются на "***".
+func Print(w io.Writer, cfg Config) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, f := range fields(reflect.TypeOf(cfg), "", nil) {
+		value, err := valueNode(reflect.ValueOf(cfg).FieldByIndex(f.index), f.secret)
+		if err != nil {
+			return err
+		}
+
+		path := strings.Split(f.key, ".")
+
+		section := root
+		for _, name := range path[:len(path)-1] {
+			section = child(section, name)
+		}
+
+		section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[len(path)-1]}, value)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func valueNode(v reflect.Value, secret bool) (*yaml.Node, error) {
+	if secret && !v.IsZero() {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: maskedSecret}, nil
+	}
+
+	node := &yaml.Node{}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return node, node.Encode(d.String())
+	}
+
+	return node, node.Encode(v.Interface())
+}
+
+// child возвращает вложенный раздел name узла parent, создавая его при первом обращении.
+func child(parent *yaml.Node, name string) *yaml.Node {
+	for i := 0; i < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == name {
+			return parent.Content[i+1]
+		}
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, node)
+
+	return node
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+
+	"TaskService/internal/ratelimit"
+	"TaskService/pkg/kafka"
+	"TaskService/pkg/logger"
+)
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// validator копит ошибки, чтобы сообщить обо всех неверных настройках сразу.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, key, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s (%s): %s", key, envName(key), fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) required(value, key string) {
+	v.check(value != "", key, "is required")
+}
+
+func (v *validator) port(value int, key string) {
+	v.check(value > 0 && value <= 65535, key, "must be a port between 1 and 65535, got %d", value)
+}
+
+func (v *validator) notNegative(value float64, key string) {
+	v.check(value >= 0, key, "must not be negative")
+}
+
+func (v *validator) oneOf(value, key string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), key, "must be one of %v, got %q", allowed, value)
+}
+
+// file проверяет, что файл по пути path существует, если путь задан.
+func (v *validator) file(path, key string) {
+	if path == "" {
+		return
+	}
+
+	_, err := os.Stat(path)
+	v.check(err == nil, key, "%v", err)
+}
+
+func (v *validator) retry(r Retry, section string) {
+	v.notNegative(r.InitialInterval.Seconds(), section+".initial_interval")
+	v.notNegative(r.MaxInterval.Seconds(), section+".max_interval")
+	v.check(r.Multiplier == 0 || r.Multiplier >= 1, section+".multiplier", "must be at least 1")
+	v.notNegative(r.Timeout.Seconds(), section+".timeout")
+	v.notNegative(float64(r.MaxAttempts), section+".max_attempts")
+}
+
+func (v *validator) kafka(k Kafka) {
+	before := len(v.errs)
+
+	v.check(len(k.Brokers) > 0 && !slices.Contains(k.Brokers, ""), "kafka.brokers", "is required")
+	v.required(k.Topic, "kafka.topic")
+
+	if k.SASL.Mechanism != "" {
+		v.oneOf(k.SASL.Mechanism, "kafka.sasl.mechanism", kafka.SASLPlain, kafka.SASLScramSHA256, kafka.SASLScramSHA512)
+		v.required(k.SASL.User, "kafka.sasl.user")
+		v.required(k.SASL.Password, "kafka.sasl.password")
+	}
+
+	v.file(k.TLS.CAFile, "kafka.tls.ca_file")
+	v.file(k.TLS.CertFile, "kafka.tls.cert_file")
+	v.file(k.TLS.KeyFile, "kafka.tls.key_file")
+	v.check((k.TLS.CertFile == "") == (k.TLS.KeyFile == ""), "kafka.tls.key_file",
+		"client certificate and key must be set together")
+
+	if k.Producer.Acks != "" {
+		v.oneOf(k.Producer.Acks, "kafka.producer.acks", kafka.AcksAll, kafka.AcksLeader, kafka.AcksNone)
+	}
+
+	if k.Producer.Compression != "" {
+		v.oneOf(k.Producer.Compression, "kafka.producer.compression", "none", "gzip", "snappy", "lz4", "zstd")
+	}
+
+	v.notNegative(float64(k.Producer.Retries), "kafka.producer.retries")
+	v.notNegative(k.Producer.Linger.Seconds(), "kafka.producer.linger")
+	v.notNegative(float64(k.Producer.BatchBytes), "kafka.producer.batch_bytes")
+	v.notNegative(float64(k.Producer.BatchMessages), "kafka.producer.batch_messages")
+
+	if len(v.errs) > before {
+		return
+	}
+
+	// сочетания настроек (идемпотентность и acks, версия и zstd) проверяет sarama
+	if _, err := kafka.NewSaramaConfig(k.Config()); err != nil {
+		v.errs = append(v.errs, err)
+	}
+}
+
+// Validate проверяет настройки до запуска компонентов и возвращает все найденные ошибки.
+// Нулевые значения допустимы там, где пакет сам подставляет значение по умолчанию.
+func (c Config) Validate() error {
+	v := &validator{}
+
+	v.port(c.Server.Port, "server.port")
+	v.port(c.GRPC.Port, "grpc.port")
+
+	v.required(c.Postgres.URL, "postgres.url")
+	v.required(c.Postgres.User, "postgres.user")
+	v.required(c.Postgres.Name, "postgres.name")
+	v.required(c.Postgres.Driver, "postgres.driver")
+	v.oneOf(c.Postgres.SSLMode, "postgres.sslmode", sslModes...)
+	v.file(c.Postgres.SSLRootCert, "postgres.sslrootcert")
+	v.file(c.Postgres.SSLCert, "postgres.sslcert")
+	v.file(c.Postgres.SSLKey, "postgres.sslkey")
+	v.check((c.Postgres.SSLCert == "") == (c.Postgres.SSLKey == ""), "postgres.sslkey",
+		"client certificate and key must be set together")
+	v.notNegative(float64(c.Postgres.MaxOpenConns), "postgres.max_open_conns")
+	v.notNegative(float64(c.Postgres.MaxIdleConns), "postgres.max_idle_conns")
+	v.notNegative(c.Postgres.ConnMaxLifetime.Seconds(), "postgres.conn_max_lifetime")
+	v.notNegative(c.Postgres.ConnMaxIdleTime.Seconds(), "postgres.conn_max_idle_time")
+
+	if c.Postgres.MaxOpenConns > 0 {
+		v.check(c.Postgres.MaxIdleConns <= c.Postgres.MaxOpenConns, "postgres.max_idle_conns",
+			"must not exceed postgres.max_open_conns (%d)", c.Postgres.MaxOpenConns)
+	}
+
+	v.kafka(c.Kafka)
+
+	if c.Logger.Level != "" {
+		_, err := logger.ParseLevel(c.Logger.Level)
+		v.check(err == nil, "logger.level", "%v", err)
+	}
+
+	for name, level := range c.Logger.NamedLevels {
+		_, err := logger.ParseLevel(level)
+		v.check(err == nil, "logger.named_levels", "%s: %v", name, err)
+	}
+
+	for name, mode := range c.Logger.Redact {
+		v.check(mode == "" || mode == logger.RedactMask || mode == logger.RedactHash,
+			"logger.redact", "%s: unknown mode %q, expected %s or %s", name, mode, logger.RedactMask, logger.RedactHash)
+	}
+
+	v.notNegative(c.Logger.SamplePeriod.Seconds(), "logger.sample_period")
+
+	v.file(c.Auth.JWKSFile, "auth.jwks_file")
+
+	v.notNegative(c.Auth.JWKSReloadInterval.Seconds(), "auth.jwks_reload_interval")
+
+	if c.RateLimit.Backend != "" {
+		v.oneOf(c.RateLimit.Backend, "ratelimit.backend", ratelimit.BackendMemory, ratelimit.BackendPostgres)
+	}
+
+	v.notNegative(c.RateLimit.ReadRate, "ratelimit.read_rate")
+	v.notNegative(float64(c.RateLimit.ReadBurst), "ratelimit.read_burst")
+	v.notNegative(c.RateLimit.WriteRate, "ratelimit.write_rate")
+	v.notNegative(float64(c.RateLimit.WriteBurst), "ratelimit.write_burst")
+
+	v.notNegative(float64(c.Tenant.MaxTasks), "tenant.max_tasks")
+
+	for name, limit := range c.Tenant.Quotas {
+		v.check(limit >= 0, "tenant.quotas", "%s: must not be negative", name)
+	}
+
+	v.check(c.Task.ProcessTimeout > 0, "task.process_timeout", "must be positive")
+	v.check(c.Task.Workers > 0, "task.workers", "must be positive")
+
+	v.notNegative(c.Health.Timeout.Seconds(), "health.timeout")
+	v.notNegative(c.Health.ShutdownDelay.Seconds(), "health.shutdown_delay")
+	v.notNegative(c.Shutdown.Timeout.Seconds(), "shutdown.timeout")
+
+	// задержка readiness входит в общий таймаут остановки и не должна его съедать
+	if c.Shutdown.Timeout > 0 {
+		v.check(c.Health.ShutdownDelay < c.Shutdown.Timeout, "health.shutdown_delay",
+			"must be less than shutdown.timeout (%s)", c.Shutdown.Timeout)
+	}
+
+	v.retry(c.StartupRetry, "startup_retry")
+	v.retry(c.ProcessRetry, "process_retry")
+
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	if c.Tracing.Endpoint != "" {
+		u, err := url.Parse(c.Tracing.Endpoint)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"tracing.endpoint", "must be an http(s) URL, got %q", c.Tracing.Endpoint)
+	}
+
+	if len(v.errs) > 0 {
+		return fmt.Errorf("invalid config:\n%w", errors.Join(v.errs...))
+	}
+
+	return nil
+}
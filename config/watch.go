@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"TaskService/pkg/logger"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Watcher перечитывает конфиг-файл при его изменении и передает новые настройки подписчикам.
+type Watcher interface {
+	// Subscribe вызывает fn с новыми настройками, когда меняется один из keys: ключ ("logger.level")
+	// или раздел целиком ("ratelimit"). Изменения ключей без подписчиков требуют перезапуска.
+	Subscribe(fn func(cfg Config), keys ...string)
+}
+
+type subscription struct {
+	keys []string
+	fn   func(cfg Config)
+}
+
+func (s subscription) covers(key string) bool {
+	return slices.ContainsFunc(s.keys, func(prefix string) bool {
+		return key == prefix || strings.HasPrefix(key, prefix+".")
+	})
+}
+
+type watcher struct {
+	v *viper.Viper
+
+	mu      sync.Mutex
+	current Config
+	subs    []subscription
+}
+
+// Watch начинает следить за конфиг-файлом, из которого загружен cfg. Новые настройки применяются,
+// только если проходят Validate. Без конфиг-файла подписчики никогда не вызываются.
+func Watch(fs *pflag.FlagSet, cfg Config) (Watcher, error) {
+	v, err := newViper(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &watcher{
+		v:       v,
+		current: cfg,
+	}
+
+	if v.ConfigFileUsed() != "" {
+		v.OnConfigChange(func(fsnotify.Event) {
+			result.reload()
+		})
+		v.WatchConfig()
+	}
+
+	return result, nil
+}
+
+func (w *watcher) Subscribe(fn func(cfg Config), keys ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subs = append(w.subs, subscription{keys: keys, fn: fn})
+}
+
+// reload вызывается viper после того, как он перечитал файл.
+func (w *watcher) reload() {
+	log := logger.WithName(logger.FromContext(context.Background()), "config")
+
+	cfg, err := decode(w.v)
+	if err == nil {
+		err = cfg.Validate()
+	}
+
+	if err != nil {
+		log.Error().Err(err).Str("file", w.v.ConfigFileUsed()).Msg("config reload failed, keeping current config")
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.apply(cfg)
+}
+
+func (w *watcher) apply(cfg Config) {
+	log := logger.WithName(logger.FromContext(context.Background()), "config")
+
+	changed := diff(w.current, cfg)
+	if len(changed) == 0 {
+		return
+	}
+
+	var applied, restart []string
+
+	for _, key := range changed {
+		if slices.ContainsFunc(w.subs, func(s subscription) bool { return s.covers(key) }) {
+			applied = append(applied, key)
+		} else {
+			restart = append(restart, key)
+		}
+	}
+
+	// ключи, требующие перезапуска, остаются в current со значениями запуска: подписчики их
+	// не видят, а предупреждение повторяется при каждой перезагрузке, пока они отличаются
+	assign(&w.current, cfg, applied)
+
+	for _, s := range w.subs {
+		if slices.ContainsFunc(changed, s.covers) {
+			s.fn(w.current)
+		}
+	}
+
+	if len(applied) > 0 {
+		log.Info().Strs("keys", applied).Msg("config reloaded")
+	}
+
+	if len(restart) > 0 {
+		log.Warn().Strs("keys", restart).Msg("config changed, restart required to apply")
+	}
+}
+
+// assign копирует в dst значения ключей keys из src.
+func assign(dst *Config, src Config, keys []string) {
+	dv, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src)
+
+	for _, f := range fields(dv.Type(), "", nil) {
+		if slices.Contains(keys, f.key) {
+			dv.FieldByIndex(f.index).Set(sv.FieldByIndex(f.index))
+		}
+	}
+}
+
+// diff возвращает ключи, значения которых в a и b различаются.
+func diff(a, b Config) []string {
+	var result []string
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	for _, f := range fields(av.Type(), "", nil) {
+		if !reflect.DeepEqual(av.FieldByIndex(f.index).Interface(), bv.FieldByIndex(f.index).Interface()) {
+			result = append(result, f.key)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Apply(t *testing.T) {
+	current := load(t)
+	w := &watcher{current: current}
+
+	var rateLimits, tasks int
+
+	w.Subscribe(func(Config) { rateLimits++ }, "ratelimit.read_rate", "ratelimit.write_rate")
+	w.Subscribe(func(Config) { tasks++ }, "task", "tenant")
+
+	next := current
+	next.RateLimit.ReadRate = 50
+	next.Task.Workers = 20
+	next.Postgres.URL = "db2:5432"
+
+	assert.ElementsMatch(t, []string{"ratelimit.read_rate", "task.workers", "postgres.url"}, diff(current, next))
+
+	w.apply(next)
+	w.apply(next)
+
+	assert.Equal(t, 1, rateLimits)
+	assert.Equal(t, 1, tasks)
+	assert.Equal(t, 20, w.current.Task.Workers)
+	// ключ без подписчика остается со значением запуска и снова попадает в изменения
+	assert.Equal(t, current.Postgres.URL, w.current.Postgres.URL)
+	assert.Equal(t, []string{"postgres.url"}, diff(w.current, next))
+}
+
+func TestWatch_FileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	Flags(fs)
+	require.NoError(t, fs.Parse([]string{"--config", path}))
+
+	cfg, err := Load(fs)
+	require.NoError(t, err)
+
+	w, err := Watch(fs, cfg)
+	require.NoError(t, err)
+
+	updated := make(chan Config, 1)
+	w.Subscribe(func(cfg Config) { updated <- cfg }, "task.workers")
+
+	changed := strings.Replace(testConfig, "kafka:\n", "task:\n  workers: 3\nkafka:\n", 1)
+	require.NoError(t, os.WriteFile(path, []byte(changed), 0o600))
+
+	select {
+	case cfg := <-updated:
+		assert.Equal(t, 3, cfg.Task.Workers)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change not applied")
+	}
+}
@@ -0,0 +1,15 @@
+#!/bin/sh
+# Инициализация базы в docker-compose: применяет миграции по порядку имен и задает пароль
+# роли сервиса task_service (APP_DB_PASSWORD). Выполняется образом postgres только при
+# создании тома с данными.
+set -e
+
+for f in /migrations/*.sql; do
+	echo "applying $f"
+	psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" -f "$f"
+done
+
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" \
+	--set password="$APP_DB_PASSWORD" <<'EOSQL'
+ALTER ROLE task_service PASSWORD :'password';
+EOSQL
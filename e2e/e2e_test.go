@@ -1,17 +1,23 @@
 package e2e
 
 import (
+	"TaskService/internal/auth"
 	"TaskService/internal/dto"
 	"TaskService/internal/service"
+	"TaskService/internal/service/task"
 	"TaskService/internal/storage"
 	"TaskService/internal/storage/postgres"
+	"TaskService/pkg/events"
 	"TaskService/pkg/kafka"
 	"TaskService/pkg/logger"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -34,6 +40,11 @@ var (
 	dbURL           string
 )
 
+const (
+	appRole     = "task_service"
+	appPassword = "task_service"
+)
+
 func TestMain(m *testing.M) {
 	ctx := context.Background()
 
@@ -108,23 +119,32 @@ func setupApplication(ctx context.Context) error {
 	}
 	broker := brokers[0]
 
-	if err := createTables(ctx); err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+	if err := applyMigrations(ctx); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
+	// сервис работает под ролью без SUPERUSER, как в docker-compose, чтобы действовал RLS
+	appURL, err := url.Parse(dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse DB connection string: %w", err)
+	}
+	appURL.User = url.UserPassword(appRole, appPassword)
+
 	pgConfig := postgres.Config{
-		URL:    dbURL,
+		URL:    appURL.String(),
 		Driver: "postgres",
 	}
 
-	storageInstance, err = storage.New(pgConfig)
+	storageInstance, err = storage.New(context.Background(), pgConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create storage: %w", err)
 	}
 
 	kafkaConfig := kafka.Config{
-		Broker: broker,
-		Topic:  "test-tasks",
+		Brokers: []string{broker},
+		Topic:   "test-tasks",
+		// сообщения, отправленные до входа обработчика в группу, тоже должны обрабатываться
+		InitialOffset: kafka.OffsetOldest,
 	}
 
 	kafkaClient, err = kafka.NewKafkaClient(kafkaConfig)
@@ -132,28 +152,45 @@ func setupApplication(ctx context.Context) error {
 		return fmt.Errorf("failed to create Kafka client: %w", err)
 	}
 
-	taskService = service.New(storageInstance, kafkaClient)
+	taskService = service.New(storageInstance, kafkaClient, task.Config{})
 
-	taskService.Task().ProcessTasks()
+	go taskService.Task().ProcessTasks(context.Background())
 
 	return nil
 }
 
-func createTables(ctx context.Context) error {
+// applyMigrations применяет migration/*.sql по порядку от имени владельца базы
+// и задает пароль роли сервиса.
+func applyMigrations(ctx context.Context) error {
 	db, err := sqlx.Connect("postgres", dbURL)
 	if err != nil {
 		return err
 	}
 	defer db.Close()
 
-	_, err = db.ExecContext(ctx, `
-		CREATE TABLE IF NOT EXISTS tasks (
-			id SERIAL PRIMARY KEY,
-			title VARCHAR(255) NOT NULL,
-			description TEXT,
-			status VARCHAR(50) DEFAULT 'created'
-		)
-	`)
+	files, err := filepath.Glob("../migration/*.sql")
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found")
+	}
+
+	// Glob возвращает файлы отсортированными по имени
+	for _, file := range files {
+		query, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, string(query)); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER ROLE %s PASSWORD '%s'", appRole, appPassword))
+
 	return err
 }
 
@@ -185,7 +222,7 @@ func teardownContainers(ctx context.Context) {
 }
 
 func TestTaskLifecycle(t *testing.T) {
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	cleanupDatabase(ctx)
 
 	t.Run("CreateTask", func(t *testing.T) {
@@ -197,7 +234,7 @@ func TestTaskLifecycle(t *testing.T) {
 		err := taskService.Task().Create(ctx, req)
 		require.NoError(t, err)
 
-		tasks, err := taskService.Task().GetList(ctx)
+		tasks, err := taskService.Task().GetList(ctx, dto.GetTaskListRequest{})
 		require.NoError(t, err)
 		require.Len(t, tasks.Tasks, 1)
 
@@ -208,7 +245,7 @@ func TestTaskLifecycle(t *testing.T) {
 	})
 
 	t.Run("GetTask", func(t *testing.T) {
-		tasks, err := taskService.Task().GetList(ctx)
+		tasks, err := taskService.Task().GetList(ctx, dto.GetTaskListRequest{})
 		require.NoError(t, err)
 		require.Len(t, tasks.Tasks, 1)
 
@@ -222,7 +259,7 @@ func TestTaskLifecycle(t *testing.T) {
 	})
 
 	t.Run("UpdateTask", func(t *testing.T) {
-		tasks, err := taskService.Task().GetList(ctx)
+		tasks, err := taskService.Task().GetList(ctx, dto.GetTaskListRequest{})
 		require.NoError(t, err)
 		require.Len(t, tasks.Tasks, 1)
 
@@ -247,7 +284,7 @@ func TestTaskLifecycle(t *testing.T) {
 }
 
 func TestTaskKafkaIntegration(t *testing.T) {
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	cleanupDatabase(ctx)
 
 	t.Run("KafkaMessageProcessing", func(t *testing.T) {
@@ -259,29 +296,27 @@ func TestTaskKafkaIntegration(t *testing.T) {
 		err := taskService.Task().Create(ctx, createReq)
 		require.NoError(t, err)
 
-		tasks, err := taskService.Task().GetList(ctx)
+		tasks, err := taskService.Task().GetList(ctx, dto.GetTaskListRequest{})
 		require.NoError(t, err)
 		require.Len(t, tasks.Tasks, 1)
 
 		taskID := tasks.Tasks[0].ID
 
-		message, err := json.Marshal(taskID)
+		message, err := json.Marshal(events.New(events.TypeTaskCreated, events.Task{ID: taskID}))
 		require.NoError(t, err)
 
-		err = kafkaClient.SendMessage(message)
+		err = kafkaClient.SendMessage(ctx, kafka.Message{Key: []byte(strconv.Itoa(taskID)), Value: message})
 		require.NoError(t, err)
 
-		time.Sleep(5 * time.Second)
-
-		task, err := taskService.Task().Get(ctx, taskID)
-		require.NoError(t, err)
-
-		assert.Equal(t, "done", task.Status)
+		require.Eventually(t, func() bool {
+			task, err := taskService.Task().Get(ctx, taskID)
+			return err == nil && task.Status == "done"
+		}, 30*time.Second, 200*time.Millisecond)
 	})
 }
 
 func TestConcurrentTaskOperations(t *testing.T) {
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	cleanupDatabase(ctx)
 
 	const numTasks = 10
@@ -320,14 +355,14 @@ func TestConcurrentTaskOperations(t *testing.T) {
 			}
 		}
 
-		tasks, err := taskService.Task().GetList(ctx)
+		tasks, err := taskService.Task().GetList(ctx, dto.GetTaskListRequest{})
 		require.NoError(t, err)
 		assert.Len(t, tasks.Tasks, numTasks)
 	})
 }
 
 func TestErrorScenarios(t *testing.T) {
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	cleanupDatabase(ctx)
 
 	t.Run("GetNonExistentTask", func(t *testing.T) {
@@ -343,7 +378,7 @@ func TestErrorScenarios(t *testing.T) {
 		err := taskService.Task().Create(ctx, createReq)
 		require.NoError(t, err)
 
-		tasks, err := taskService.Task().GetList(ctx)
+		tasks, err := taskService.Task().GetList(ctx, dto.GetTaskListRequest{})
 		require.NoError(t, err)
 		taskID := tasks.Tasks[0].ID
 
@@ -364,7 +399,7 @@ func TestPerformance(t *testing.T) {
 		t.Skip("Skipping performance test in short mode")
 	}
 
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	cleanupDatabase(ctx)
 
 	const batchSize = 100
@@ -386,7 +421,7 @@ func TestPerformance(t *testing.T) {
 		t.Logf("Created %d tasks in %v", batchSize, createDuration)
 
 		start = time.Now()
-		tasks, err := taskService.Task().GetList(ctx)
+		tasks, err := taskService.Task().GetList(ctx, dto.GetTaskListRequest{})
 		require.NoError(t, err)
 		readDuration := time.Since(start)
 
@@ -3,80 +3,320 @@ package app
 import (
 	"TaskService/pkg/kafka"
 	"TaskService/pkg/logger"
+	"TaskService/pkg/retry"
+	"TaskService/pkg/tracing"
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"time"
 
 	"TaskService/config"
+	"TaskService/internal/auth"
 	"TaskService/internal/handler"
+	"TaskService/internal/health"
+	"TaskService/internal/lifecycle"
+	"TaskService/internal/ratelimit"
+	"TaskService/internal/rpc"
 	"TaskService/internal/service"
 	"TaskService/internal/storage"
 )
 
 type App struct {
-	server *http.Server
-	kc     kafka.Kafka
+	mode      Mode
+	server    *http.Server
+	rpc       *rpc.Server
+	rpcAddr   string
+	srv       service.Service
+	health    health.Checker
+	lifecycle lifecycle.Manager
+	// streams закрывается при остановке и завершает SSE и WebSocket соединения
+	streams chan struct{}
 }
 
-func New() (*App, error) {
-	db, err := storage.New(config.Psql())
+// New собирает компоненты, нужные режиму mode.
+// Недоступные при старте Postgres и Kafka ждет с повторными попытками, пока не отменен ctx.
+// Компоненты, которые умеют менять настройки на лету, подписываются на watcher.
+func New(ctx context.Context, mode Mode, cfg config.Config, watcher config.Watcher) (_ *App, err error) {
+	lc := lifecycle.New(cfg.Shutdown.Config())
+
+	// при ошибке закрываем то, что уже успели открыть
+	defer func() {
+		if err != nil {
+			_ = lc.Shutdown(context.Background())
+		}
+	}()
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing.Config())
 	if err != nil {
 		return nil, err
 	}
 
-	kc, err := kafka.NewKafkaClient(config.Kfk())
+	// спаны досылаются последними, после остановки всех компонентов
+	lc.Add("tracing", lifecycle.StopFunc(shutdownTracing))
+
+	retryCfg := cfg.StartupRetry.Config()
+
+	var db storage.Storage
+
+	err = retry.Do(ctx, "postgres", retryCfg, func(ctx context.Context) (err error) {
+		db, err = storage.New(ctx, cfg.Postgres.Config())
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	srv := service.New(db, kc)
+	lc.Add("postgres", func(context.Context) error {
+		return db.Close()
+	})
+
+	var kc kafka.Kafka
 
-	go srv.Task().ProcessTasks()
+	err = retry.Do(ctx, "kafka", retryCfg, func(context.Context) (err error) {
+		kc, err = kafka.NewKafkaClient(cfg.Kafka.Config())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Close закрывает producer, дождавшись подтверждения отправленных сообщений
+	lc.Add("kafka", func(context.Context) error {
+		return kc.Close()
+	})
+
+	srv := service.New(db, kc, cfg.TaskConfig())
+
+	watcher.Subscribe(func(cfg config.Config) {
+		srv.Task().SetConfig(cfg.TaskConfig())
+	}, "task", "tenant", "process_retry")
+
+	checks := map[string]health.Check{
+		"postgres": db.Ping,
+		"kafka":    kc.Ping,
+	}
+
+	if mode.worker() {
+		checks["kafka_consumer"] = func(context.Context) error {
+			if !kc.ConsumerAlive() {
+				return errors.New("consumer is not running")
+			}
+			return nil
+		}
+	}
+
+	checker := health.New(cfg.Health.Config(), checks)
 
 	result := &App{
-		server: &http.Server{
-			Addr:    config.Srv(),
-			Handler: handler.New(srv),
-		},
-		kc: kc,
+		mode:      mode,
+		srv:       srv,
+		health:    checker,
+		lifecycle: lc,
+		streams:   make(chan struct{}),
+	}
+
+	if !mode.api() {
+		result.server = &http.Server{
+			Addr:    cfg.Server.Addr(),
+			Handler: handler.NewProbes(checker),
+		}
+
+		return result, nil
 	}
 
+	handlerCfg, err := apiConfig(cfg, watcher, srv, db, checker)
+	if err != nil {
+		return nil, err
+	}
+
+	handlerCfg.Done = result.streams
+
+	result.server = &http.Server{
+		Addr:    cfg.Server.Addr(),
+		Handler: handler.New(srv, handlerCfg),
+	}
+	result.rpc = rpc.New(srv, handlerCfg.Authenticator)
+	result.rpcAddr = cfg.GRPC.Addr()
+
 	return result, nil
 }
 
-func (a *App) Run(ctx context.Context) error {
-	log := logger.Get()
+// apiConfig настраивает аутентификацию и ограничение частоты запросов API.
+func apiConfig(cfg config.Config, watcher config.Watcher, srv service.Service, db storage.Storage, checker health.Checker) (handler.Config, error) {
+	authCfg := cfg.Auth.Config()
 
-	go func() {
-		select {
-		case <-ctx.Done():
-			fmt.Println("Shutting down the server...")
+	handlerCfg := handler.Config{
+		SwaggerPublic: authCfg.SwaggerPublic,
+		Health:        checker,
+	}
+
+	if authCfg.Enabled {
+		authenticators := []auth.Authenticator{auth.NewAPIKey(srv.APIKey())}
 
-			err := a.server.Shutdown(context.Background())
+		if authCfg.Secret != "" || authCfg.JWKSFile != "" {
+			jwtAuth, err := auth.NewJWT(authCfg)
 			if err != nil {
-				fmt.Println(err)
+				return handlerCfg, err
 			}
 
-			err = a.kc.Close()
-			if err != nil {
-				log.Info().Err(err).Msg("Kafka client connection closed.")
-				return
+			authenticators = append(authenticators, jwtAuth)
+		}
+
+		handlerCfg.Authenticator = auth.Chain(authenticators...)
+	}
+
+	if rlCfg := cfg.RateLimit.Config(); rlCfg.Enabled {
+		var store ratelimit.Store
+
+		switch rlCfg.Backend {
+		case "", ratelimit.BackendMemory:
+			store = ratelimit.NewMemoryStore()
+		case ratelimit.BackendPostgres:
+			store = db.RateLimits()
+		default:
+			return handlerCfg, fmt.Errorf("unknown rate limit backend %q", rlCfg.Backend)
+		}
+
+		limiter := ratelimit.New(rlCfg, store)
+
+		// включение и хранилище бакетов меняются только перезапуском
+		watcher.Subscribe(func(cfg config.Config) {
+			rlCfg := cfg.RateLimit.Config()
+			limiter.SetLimits(rlCfg.Read, rlCfg.Write)
+		}, "ratelimit.read_rate", "ratelimit.read_burst", "ratelimit.write_rate", "ratelimit.write_burst")
+
+		handlerCfg.RateLimiter = limiter
+	}
+
+	return handlerCfg, nil
+}
+
+// Run запускает серверы и обработку задач и возвращается, когда после отмены ctx
+// все компоненты остановлены: readiness → HTTP → gRPC → обработка задач и чтение событий → Kafka → Postgres → трассировка.
+func (a *App) Run(ctx context.Context) error {
+	log := logger.Get()
+
+	// серверы и обработчик пишут сюда ошибки, из-за которых сервис нужно остановить
+	failed := make(chan error, 4)
+
+	if a.mode.worker() {
+		a.startWorker(failed)
+	}
+
+	if a.mode.api() {
+		a.startEvents(failed)
+	}
+
+	if a.rpc != nil {
+		lis, err := net.Listen("tcp", a.rpcAddr)
+		if err != nil {
+			_ = a.lifecycle.Shutdown(context.Background())
+			return fmt.Errorf("failed to listen grpc: %w", err)
+		}
+
+		go func() {
+			if err := a.rpc.Serve(lis); err != nil {
+				failed <- fmt.Errorf("grpc server: %w", err)
 			}
+		}()
 
-			fmt.Println("Server shutting down successfully")
+		a.lifecycle.Add("grpc", a.rpc.Shutdown)
+	}
 
-			return
+	go func() {
+		if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			failed <- fmt.Errorf("http server: %w", err)
 		}
 	}()
 
-	log.Info().Msg("Server started")
+	a.lifecycle.Add("http", func(ctx context.Context) error {
+		close(a.streams)
+		return a.server.Shutdown(ctx)
+	})
 
-	if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		return err
+	// readiness отвечает 503, пока балансировщик выводит реплику из ротации
+	a.lifecycle.Add("readiness", func(ctx context.Context) error {
+		a.health.Shutdown()
+
+		select {
+		case <-time.After(a.health.ShutdownDelay()):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	log.Info().Str("mode", string(a.mode)).Msg("Server started")
+
+	var runErr error
+
+	select {
+	case <-ctx.Done():
+	case runErr = <-failed:
+		log.Error().Err(runErr).Msg("server failed")
+	}
+
+	log.Info().Msg("Shutting down the server")
+
+	if err := a.lifecycle.Shutdown(context.Background()); err != nil {
+		return errors.Join(runErr, err)
 	}
 
 	log.Info().Msg("Server stopped")
 
-	return nil
+	return runErr
+}
+
+// startWorker запускает обработку задач из Kafka. Остановка прекращает чтение сообщений
+// и ждет завершения уже начатых задач в пределах таймаута остановки.
+func (a *App) startWorker(failed chan<- error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if err := a.srv.Task().ProcessTasks(ctx); err != nil {
+			failed <- fmt.Errorf("task processing: %w", err)
+		}
+	}()
+
+	a.lifecycle.Add("worker", func(stopCtx context.Context) error {
+		cancel()
+
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return fmt.Errorf("in-flight tasks not finished: %w", stopCtx.Err())
+		}
+	})
+}
+
+// startEvents передает потокам /tasks/events, /ws и Watch события задач из Kafka, в том числе
+// изменения, сделанные обработчиками в других процессах.
+func (a *App) startEvents(failed chan<- error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if err := a.srv.Task().ConsumeEvents(ctx); err != nil {
+			failed <- fmt.Errorf("task events: %w", err)
+		}
+	}()
+
+	a.lifecycle.Add("events", func(stopCtx context.Context) error {
+		cancel()
+
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	})
 }
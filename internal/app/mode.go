@@ -0,0 +1,34 @@
+package app
+
+import "fmt"
+
+// Mode определяет, какие компоненты запускает процесс.
+type Mode string
+
+const (
+	// ModeAPI HTTP и gRPC API без обработки задач из Kafka.
+	ModeAPI Mode = "api"
+	// ModeWorker обработка задач из Kafka. HTTP отдает только /healthz, /readyz и /metrics.
+	ModeWorker Mode = "worker"
+	// ModeAll API и обработка задач в одном процессе.
+	ModeAll Mode = "all"
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeAPI, ModeWorker, ModeAll:
+		return m, nil
+	case "":
+		return ModeAll, nil
+	default:
+		return "", fmt.Errorf("unknown mode %q, expected api, worker or all", s)
+	}
+}
+
+func (m Mode) api() bool {
+	return m == ModeAPI || m == ModeAll
+}
+
+func (m Mode) worker() bool {
+	return m == ModeWorker || m == ModeAll
+}
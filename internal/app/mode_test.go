@@ -0,0 +1,32 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Mode
+		api    bool
+		worker bool
+	}{
+		{value: "api", want: ModeAPI, api: true},
+		{value: "worker", want: ModeWorker, worker: true},
+		{value: "all", want: ModeAll, api: true, worker: true},
+		{value: "", want: ModeAll, api: true, worker: true},
+	}
+
+	for _, tt := range tests {
+		mode, err := ParseMode(tt.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, mode)
+		assert.Equal(t, tt.api, mode.api())
+		assert.Equal(t, tt.worker, mode.worker())
+	}
+
+	_, err := ParseMode("scheduler")
+	assert.Error(t, err)
+}
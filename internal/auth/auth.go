@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Chain пробует аутентификаторы по очереди. Каждый возвращает ErrMissingCredentials,
+// если запрос не содержит его схемы; любая другая ошибка прерывает проверку.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+type chain []Authenticator
+
+func (c chain) Authenticate(r *http.Request) (Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrMissingCredentials) {
+			continue
+		}
+
+		return p, err
+	}
+
+	return Principal{}, ErrMissingCredentials
+}
+
+type APIKeyVerifier interface {
+	Authenticate(ctx context.Context, key string) (Principal, error)
+}
+
+type apiKeyAuthenticator struct {
+	verifier APIKeyVerifier
+}
+
+// NewAPIKey аутентифицирует запросы с заголовком Authorization: ApiKey <key>.
+func NewAPIKey(verifier APIKeyVerifier) Authenticator {
+	return &apiKeyAuthenticator{verifier: verifier}
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	key := credentials(r, "ApiKey")
+	if key == "" {
+		return Principal{}, ErrMissingCredentials
+	}
+
+	return a.verifier.Authenticate(r.Context(), key)
+}
+
+// bearerToken достает токен из заголовка Authorization: Bearer. Для браузерных
+// EventSource и WebSocket, которые не умеют задавать заголовки, допускается ?access_token=.
+func bearerToken(r *http.Request) string {
+	if token := credentials(r, "Bearer"); token != "" {
+		return token
+	}
+
+	if r.Header.Get("Authorization") != "" {
+		return ""
+	}
+
+	return r.URL.Query().Get("access_token")
+}
+
+func credentials(r *http.Request, scheme string) string {
+	s, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(s, scheme) {
+		return ""
+	}
+
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,28 @@
+package auth
+
+import "time"
+
+const (
+	defaultJWKSReloadInterval = time.Minute
+)
+
+type Config struct {
+	Enabled bool
+	// Secret ключ для HS256. Пустой отключает HS256.
+	Secret string
+	// JWKSFile путь к локальному JWKS с ключами RS256/ES256. Пустой отключает RS256/ES256.
+	JWKSFile string
+	// JWKSReloadInterval как часто проверять изменение JWKS-файла.
+	JWKSReloadInterval time.Duration
+	Issuer             string
+	Audience           string
+	SwaggerPublic      bool
+}
+
+func validateConfig(cfg Config) Config {
+	if cfg.JWKSReloadInterval == 0 {
+		cfg.JWKSReloadInterval = defaultJWKSReloadInterval
+	}
+
+	return cfg
+}
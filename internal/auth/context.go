@@ -0,0 +1,54 @@
+package auth
+
+import "context"
+
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+	ScopeAdmin      = "admin"
+)
+
+type (
+	principalContextKey struct{}
+	trustedContextKey   struct{}
+)
+
+// Principal аутентифицированный субъект запроса.
+type Principal struct {
+	Subject string
+	Scopes  []string
+	// Tenant тенант, к которому привязан субъект. Пустой — тенант выбирается заголовком запроса.
+	Tenant string
+	Claims map[string]interface{}
+}
+
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok
+}
+
+// WithTrusted помечает вызов без Principal как доверенный: обработку сообщений Kafka внутри
+// сервиса или запрос к API с выключенной аутентификацией. Таким вызовам доступны все задачи,
+// остальные вызовы без Principal не получают доступа.
+func WithTrusted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trustedContextKey{}, true)
+}
+
+func Trusted(ctx context.Context) bool {
+	trusted, _ := ctx.Value(trustedContextKey{}).(bool)
+	return trusted
+}
@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet ключи из локального JWKS-файла. Файл перечитывается, если
+// с последней проверки прошло interval и изменилось время модификации.
+type keySet struct {
+	path     string
+	interval time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func newKeySet(path string, interval time.Duration) (*keySet, error) {
+	ks := &keySet{
+		path:     path,
+		interval: interval,
+	}
+
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+func (ks *keySet) Reload() error {
+	info, err := os.Stat(ks.path)
+	if err != nil {
+		return fmt.Errorf("stat jwks file: %w", err)
+	}
+
+	data, err := os.ReadFile(ks.path)
+	if err != nil {
+		return fmt.Errorf("read jwks file: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.modTime = info.ModTime()
+	ks.lastCheck = time.Now()
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// Key возвращает ключ по kid. Если kid пуст и ключ в наборе один, возвращается он.
+func (ks *keySet) Key(kid string) (interface{}, error) {
+	ks.reloadIfChanged()
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, nil
+		}
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return key, nil
+}
+
+func (ks *keySet) reloadIfChanged() {
+	ks.mu.RLock()
+	due := time.Since(ks.lastCheck) >= ks.interval
+	modTime := ks.modTime
+	ks.mu.RUnlock()
+
+	if !due {
+		return
+	}
+
+	info, err := os.Stat(ks.path)
+	if err != nil || info.ModTime().Equal(modTime) {
+		ks.mu.Lock()
+		ks.lastCheck = time.Now()
+		ks.mu.Unlock()
+		return
+	}
+
+	// При ошибке разбора остаются прежние ключи.
+	if err := ks.Reload(); err != nil {
+		ks.mu.Lock()
+		ks.lastCheck = time.Now()
+		ks.mu.Unlock()
+	}
+}
+
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("parse jwk %q: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no signing keys")
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type jwtAuthenticator struct {
+	secret []byte
+	keys   *keySet
+	parser *jwt.Parser
+}
+
+// NewJWT создает аутентификатор Bearer-токенов: HS256 с общим секретом
+// и RS256/ES256 с ключами из JWKS-файла.
+func NewJWT(cfg Config) (Authenticator, error) {
+	cfg = validateConfig(cfg)
+
+	result := &jwtAuthenticator{}
+
+	var methods []string
+
+	if cfg.Secret != "" {
+		result.secret = []byte(cfg.Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := newKeySet(cfg.JWKSFile, cfg.JWKSReloadInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		result.keys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("auth: jwt secret or jwks file is required")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods)}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	result.parser = jwt.NewParser(opts...)
+
+	return result, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	raw := bearerToken(r)
+	if raw == "" {
+		return Principal{}, ErrMissingCredentials
+	}
+
+	claims := jwt.MapClaims{}
+
+	if _, err := a.parser.ParseWithClaims(raw, claims, a.key); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Principal{}, fmt.Errorf("%w: missing subject", ErrInvalidCredentials)
+	}
+
+	tenant, _ := claims["tenant_id"].(string)
+
+	result := Principal{
+		Subject: subject,
+		Scopes:  scopes(claims),
+		Tenant:  tenant,
+		Claims:  claims,
+	}
+
+	return result, nil
+}
+
+// scopes читает права из claim scope (строка через пробел, RFC 8693) или scp (массив).
+func scopes(claims jwt.MapClaims) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+
+	list, _ := claims["scp"].([]interface{})
+
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+func (a *jwtAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return a.secret, nil
+
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		kid, _ := token.Header["kid"].(string)
+		return a.keys.Key(kid)
+
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	raw, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return raw
+}
+
+func authenticate(t *testing.T, a Authenticator, token string) (Principal, error) {
+	t.Helper()
+
+	r := httptest.NewRequest("GET", "/tasks", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	return a.Authenticate(r)
+}
+
+func encode(b *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(b.Bytes())
+}
+
+func writeJWKS(t *testing.T, path string, keys ...jwk) {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestJWT_HS256(t *testing.T) {
+	a, err := NewJWT(Config{Secret: "secret", Issuer: "issuer"})
+	require.NoError(t, err)
+
+	token := sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{
+		"sub":  "user-1",
+		"iss":  "issuer",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"role": "admin",
+	})
+
+	p, err := authenticate(t, a, token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", p.Subject)
+	assert.Equal(t, "admin", p.Claims["role"])
+
+	_, err = authenticate(t, a, sign(t, jwt.SigningMethodHS256, "", []byte("other"), jwt.MapClaims{"sub": "user-1", "iss": "issuer"}))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = authenticate(t, a, sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{
+		"sub": "user-1",
+		"iss": "issuer",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	}))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = a.Authenticate(httptest.NewRequest("GET", "/tasks", nil))
+	assert.ErrorIs(t, err, ErrMissingCredentials)
+}
+
+func TestJWT_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path,
+		jwk{Kty: "RSA", Kid: "rsa-1", N: encode(rsaKey.N), E: encode(big.NewInt(int64(rsaKey.E)))},
+		jwk{Kty: "EC", Kid: "ec-1", Crv: "P-256", X: encode(ecKey.X), Y: encode(ecKey.Y)},
+	)
+
+	a, err := NewJWT(Config{JWKSFile: path})
+	require.NoError(t, err)
+
+	p, err := authenticate(t, a, sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.MapClaims{"sub": "rsa-user"}))
+	require.NoError(t, err)
+	assert.Equal(t, "rsa-user", p.Subject)
+
+	p, err = authenticate(t, a, sign(t, jwt.SigningMethodES256, "ec-1", ecKey, jwt.MapClaims{"sub": "ec-user"}))
+	require.NoError(t, err)
+	assert.Equal(t, "ec-user", p.Subject)
+
+	_, err = authenticate(t, a, sign(t, jwt.SigningMethodRS256, "unknown", rsaKey, jwt.MapClaims{"sub": "rsa-user"}))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	// HS256 не принимается, если секрет не настроен.
+	_, err = authenticate(t, a, sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{"sub": "user"}))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestJWT_JWKSReload(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, jwk{Kty: "RSA", Kid: "old", N: encode(oldKey.N), E: encode(big.NewInt(int64(oldKey.E)))})
+
+	a, err := NewJWT(Config{JWKSFile: path, JWKSReloadInterval: time.Nanosecond})
+	require.NoError(t, err)
+
+	token := sign(t, jwt.SigningMethodRS256, "new", newKey, jwt.MapClaims{"sub": "user"})
+
+	_, err = authenticate(t, a, token)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	writeJWKS(t, path, jwk{Kty: "RSA", Kid: "new", N: encode(newKey.N), E: encode(big.NewInt(int64(newKey.E)))})
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+
+	_, err = authenticate(t, a, token)
+	assert.NoError(t, err)
+}
+
+func TestNewJWT_RequiresKeys(t *testing.T) {
+	_, err := NewJWT(Config{Enabled: true})
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package auth
+
+// Role уровень доступа к задачам.
+type Role string
+
+const (
+	// RoleNone у субъекта нет прав на задачи: ни claim "role", ни scopes tasks:*/admin.
+	RoleNone   Role = ""
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// IsAdmin администратор — субъект с claim "role" admin или со scope admin.
+func (p Principal) IsAdmin() bool {
+	role, _ := p.Claims["role"].(string)
+
+	return Role(role) == RoleAdmin || p.HasScope(ScopeAdmin)
+}
+
+// Role определяет роль субъекта: администратор по IsAdmin, иначе claim "role" токена,
+// иначе роль выводится из scopes. Без подходящих scopes роль RoleNone.
+func (p Principal) Role() Role {
+	if p.IsAdmin() {
+		return RoleAdmin
+	}
+
+	if role, ok := p.Claims["role"].(string); ok {
+		switch Role(role) {
+		case RoleViewer, RoleEditor, RoleAdmin:
+			return Role(role)
+		}
+	}
+
+	switch {
+	case p.HasScope(ScopeTasksWrite):
+		return RoleEditor
+	case p.HasScope(ScopeTasksRead):
+		return RoleViewer
+	default:
+		return RoleNone
+	}
+}
+
+func (r Role) CanRead() bool {
+	return r == RoleViewer || r == RoleEditor || r == RoleAdmin
+}
+
+func (r Role) CanWrite() bool {
+	return r == RoleEditor || r == RoleAdmin
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal_Role(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		admin     bool
+		role      Role
+	}{
+		{name: "no scopes", principal: Principal{}, role: RoleNone},
+		{name: "api key with empty scopes", principal: Principal{Subject: "apikey:1", Scopes: []string{}}, role: RoleNone},
+		{name: "unrelated scopes", principal: Principal{Scopes: []string{"reports:read"}}, role: RoleNone},
+		{name: "read scope", principal: Principal{Scopes: []string{ScopeTasksRead}}, role: RoleViewer},
+		{name: "write scope", principal: Principal{Scopes: []string{ScopeTasksWrite}}, role: RoleEditor},
+		{name: "admin scope", principal: Principal{Scopes: []string{ScopeAdmin}}, admin: true, role: RoleAdmin},
+		{name: "admin claim", principal: Principal{Claims: map[string]interface{}{"role": "admin"}}, admin: true, role: RoleAdmin},
+		{
+			name:      "admin scope with viewer claim",
+			principal: Principal{Scopes: []string{ScopeAdmin}, Claims: map[string]interface{}{"role": "viewer"}},
+			admin:     true,
+			role:      RoleAdmin,
+		},
+		{
+			name:      "viewer claim with write scope",
+			principal: Principal{Scopes: []string{ScopeTasksWrite}, Claims: map[string]interface{}{"role": "viewer"}},
+			role:      RoleViewer,
+		},
+		{name: "unknown claim", principal: Principal{Claims: map[string]interface{}{"role": "root"}}, role: RoleNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.admin, tt.principal.IsAdmin())
+			assert.Equal(t, tt.role, tt.principal.Role())
+			assert.Equal(t, tt.role != RoleNone, tt.principal.Role().CanRead())
+		})
+	}
+}
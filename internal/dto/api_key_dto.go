@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"TaskService/internal/model"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" example:"nightly-export"`
+	Scopes    []string   `json:"scopes" example:"tasks:read"`
+	TenantID  string     `json:"tenant_id,omitempty" example:"sales"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type GetAPIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix" example:"tsk_1a2b3c4d"`
+	Scopes     []string   `json:"scopes"`
+	TenantID   string     `json:"tenant_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func NewGetAPIKeyResponse(key model.APIKey) GetAPIKeyResponse {
+	return GetAPIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		TenantID:   key.TenantID,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+// CreateAPIKeyResponse содержит ключ в открытом виде. Повторно получить его нельзя.
+type CreateAPIKeyResponse struct {
+	GetAPIKeyResponse
+	Key string `json:"key" example:"tsk_1a2b3c4d_XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX"`
+}
+
+type GetAPIKeyListResponse struct {
+	APIKeys []GetAPIKeyResponse `json:"api_keys"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+type HealthResponse struct {
+	Status string `json:"status" example:"ok"`
+	// Checks результат проверки каждой зависимости, только для /readyz
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+type HealthCheck struct {
+	Status string `json:"status" example:"ok"`
+	Error  string `json:"error,omitempty" example:"dial tcp 127.0.0.1:5432: connect: connection refused"`
+	// DurationMs время проверки в миллисекундах
+	DurationMs int64 `json:"duration_ms" example:"3"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+type LogLevelResponse struct {
+	Level string            `json:"level" example:"INFO"`
+	Named map[string]string `json:"named" example:"kafka:DEBUG"`
+}
+
+type UpdateLogLevelRequest struct {
+	// Level общий уровень: DEBUG, INFO, WARNING, ERROR, FATAL, PANIC
+	Level string `json:"level" example:"DEBUG"`
+	// Named заменяет уровни отдельных логгеров. Не передан — переопределения не меняются.
+	Named map[string]string `json:"named,omitempty" example:"postgres:WARNING"`
+}
@@ -1,8 +1,15 @@
 package dto
 
+import (
+	"TaskService/internal/model"
+	"TaskService/internal/service/event"
+	"time"
+)
+
 type CreateTaskRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	Assignee    string `json:"assignee"`
 }
 
 type GetTaskResponse struct {
@@ -10,6 +17,26 @@ type GetTaskResponse struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
+	CreatedBy   string `json:"created_by"`
+	Assignee    string `json:"assignee"`
+}
+
+func NewGetTaskResponse(task model.Task) GetTaskResponse {
+	return GetTaskResponse{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		CreatedBy:   task.CreatedBy,
+		Assignee:    task.Assignee,
+	}
+}
+
+type GetTaskListRequest struct {
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
 }
 
 type GetTaskListResponse struct {
@@ -21,4 +48,29 @@ type UpdateTaskRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
+	// Assignee не меняется, если поле не передано
+	Assignee *string `json:"assignee,omitempty"`
+}
+
+type TaskEventResponse struct {
+	ID   string           `json:"id,omitempty" example:"5f0c2a8e-7c1b-4d1e-9a57-3c2f1f3b7e0a:task.updated"`
+	Type string           `json:"type" example:"task.updated"`
+	Time time.Time        `json:"time"`
+	Task *GetTaskResponse `json:"task,omitempty"`
+}
+
+// NewTaskEventResponse переводит событие подписки в ответ. У события resync нет ID и задачи.
+func NewTaskEventResponse(e event.Event) TaskEventResponse {
+	resp := TaskEventResponse{
+		ID:   e.ID,
+		Type: string(e.Type),
+		Time: e.Time,
+	}
+
+	if e.Type != event.TypeResync {
+		task := NewGetTaskResponse(e.Task)
+		resp.Task = &task
+	}
+
+	return resp
 }
@@ -0,0 +1,24 @@
+package dto
+
+const (
+	WSTypeSubscribe   = "subscribe"
+	WSTypeUnsubscribe = "unsubscribe"
+	WSTypeSnapshot    = "snapshot"
+	WSTypeEvent       = "event"
+	WSTypeError       = "error"
+)
+
+type WSClientMessage struct {
+	Type    string `json:"type" example:"subscribe"`
+	ID      string `json:"id" example:"my-tasks"`
+	TaskIDs []int  `json:"task_ids,omitempty"`
+	Status  string `json:"status,omitempty" example:"created"`
+}
+
+type WSServerMessage struct {
+	Type         string             `json:"type" example:"snapshot"`
+	Subscription string             `json:"subscription,omitempty" example:"my-tasks"`
+	Tasks        []GetTaskResponse  `json:"tasks,omitempty"`
+	Event        *TaskEventResponse `json:"event,omitempty"`
+	Error        string             `json:"error,omitempty"`
+}
@@ -0,0 +1,138 @@
+package apikey
+
+import (
+	"TaskService/internal/dto"
+	"TaskService/internal/service"
+	"TaskService/internal/service/apikey"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	service service.Service
+}
+
+func New(service service.Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// CreateAPIKeyHandler выпускает новый API-ключ
+// @Summary Create an API key
+// @Description Create a long-lived API key for service-to-service clients. The key is returned only once.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} dto.CreateAPIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys [post]
+func (h *Handler) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	resp, err := h.service.APIKey().Create(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apikey.ErrInvalidName):
+			writeErrorResponse(w, http.StatusBadRequest, "Name is required")
+		case errors.Is(err, apikey.ErrInvalidScope):
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid scope")
+		case errors.Is(err, apikey.ErrInvalidTenant):
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid tenant")
+		case errors.Is(err, apikey.ErrForbidden):
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to create API key")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, resp)
+}
+
+// GetAPIKeyListHandler возвращает список API-ключей
+// @Summary List API keys
+// @Description List API keys without their secret values
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.GetAPIKeyListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys [get]
+func (h *Handler) GetAPIKeyListHandler(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.service.APIKey().List(r.Context())
+	if err != nil {
+		if errors.Is(err, apikey.ErrForbidden) {
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to get API keys")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// RevokeAPIKeyHandler отзывает API-ключ
+// @Summary Revoke an API key
+// @Tags admin
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	err = h.service.APIKey().Revoke(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, apikey.ErrNotFound):
+			writeErrorResponse(w, http.StatusNotFound, "API key not found")
+		case errors.Is(err, apikey.ErrForbidden):
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to revoke API key")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, dto.NewSuccessResponse("API key revoked successfully"))
+}
+
+func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	writeJSONResponse(w, statusCode, dto.NewErrorResponse(message))
+}
@@ -3,36 +3,127 @@ package handler
 import (
 	"net/http"
 
+	"TaskService/internal/auth"
+	"TaskService/internal/handler/apikey"
+	healthHandler "TaskService/internal/handler/health"
+	"TaskService/internal/handler/loglevel"
+	"TaskService/internal/handler/middleware"
 	"TaskService/internal/handler/task"
+	"TaskService/internal/handler/ws"
+	"TaskService/internal/health"
+	"TaskService/internal/ratelimit"
 	"TaskService/internal/service"
 
 	_ "TaskService/docs"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+type Config struct {
+	// Authenticator проверяет запросы к API. nil оставляет API открытым,
+	// но /admin/ без аутентифицированного администратора всегда отвечает 403.
+	Authenticator auth.Authenticator
+	// SwaggerPublic открывает /swagger/ без аутентификации.
+	SwaggerPublic bool
+	// RateLimiter ограничивает частоту запросов к API. nil отключает ограничение.
+	RateLimiter ratelimit.Limiter
+	// Health проверяет зависимости для /readyz. nil — /readyz не регистрируется.
+	Health health.Checker
+	// Done закрывается при остановке сервера: потоки /tasks/events и /ws завершаются,
+	// иначе http.Server.Shutdown ждал бы их до таймаута.
+	Done <-chan struct{}
+}
+
 type Handler struct {
 	srv    service.Service
 	router chi.Router
 }
 
-func New(srv service.Service) http.Handler {
+func New(srv service.Service, cfg Config) http.Handler {
 	handler := &Handler{
 		srv:    srv,
 		router: chi.NewRouter(),
 	}
 
-	taskHandler := task.New(srv)
+	taskHandler := task.New(srv, cfg.Done)
+	wsHandler := ws.New(srv, cfg.Done)
+	apiKeyHandler := apikey.New(srv)
+	logLevelHandler := loglevel.New()
+
+	handler.router.Use(middleware.Metrics)
+	handler.router.Use(middleware.Tracing)
+	handler.router.Use(middleware.RequestLogger)
+
+	handler.router.Handle("/metrics", promhttp.Handler())
+
+	probes := healthHandler.New(cfg.Health)
+	handler.router.Get("/healthz", probes.LivenessHandler)
+
+	if cfg.Health != nil {
+		handler.router.Get("/readyz", probes.ReadinessHandler)
+	}
+
+	if cfg.SwaggerPublic {
+		handler.router.Get("/swagger/*", httpSwagger.Handler())
+	}
+
+	handler.router.Group(func(r chi.Router) {
+		if cfg.Authenticator != nil {
+			r.Use(middleware.Auth(cfg.Authenticator))
+		} else {
+			r.Use(middleware.Trusted)
+		}
+
+		r.Use(middleware.Tenant())
 
-	handler.router.Get("/swagger/*", httpSwagger.Handler())
+		if cfg.RateLimiter != nil {
+			r.Use(middleware.RateLimit(cfg.RateLimiter))
+		}
 
-	handler.router.Route("/tasks", func(r chi.Router) {
-		r.Get("/", taskHandler.GetTaskListHandler)
-		r.Post("/", taskHandler.CreateTaskHandler)
-		r.Put("/", taskHandler.UpdateTaskHandler)
-		r.Get("/{id}", taskHandler.GetTaskHandler)
+		if !cfg.SwaggerPublic {
+			r.Get("/swagger/*", httpSwagger.Handler())
+		}
+
+		r.Get("/ws", wsHandler.SubscribeHandler)
+
+		r.Route("/tasks", func(r chi.Router) {
+			r.Get("/", taskHandler.GetTaskListHandler)
+			r.Post("/", taskHandler.CreateTaskHandler)
+			r.Put("/", taskHandler.UpdateTaskHandler)
+			r.Get("/events", taskHandler.TaskEventsHandler)
+			r.Get("/{id}", taskHandler.GetTaskHandler)
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireAdmin)
+
+			r.Route("/api-keys", func(r chi.Router) {
+				r.Get("/", apiKeyHandler.GetAPIKeyListHandler)
+				r.Post("/", apiKeyHandler.CreateAPIKeyHandler)
+				r.Delete("/{id}", apiKeyHandler.RevokeAPIKeyHandler)
+			})
+
+			r.Get("/log-level", logLevelHandler.GetLogLevelHandler)
+			r.Put("/log-level", logLevelHandler.UpdateLogLevelHandler)
+		})
 	})
 
 	return handler.router
 }
+
+// NewProbes создает обработчик для процесса без API (режим worker): только пробы и метрики.
+func NewProbes(checker health.Checker) http.Handler {
+	router := chi.NewRouter()
+
+	router.Use(middleware.Metrics)
+
+	probes := healthHandler.New(checker)
+
+	router.Get("/healthz", probes.LivenessHandler)
+	router.Get("/readyz", probes.ReadinessHandler)
+	router.Handle("/metrics", promhttp.Handler())
+
+	return router
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"TaskService/internal/dto"
+	"TaskService/internal/health"
+	"encoding/json"
+	"net/http"
+)
+
+type Handler struct {
+	checker health.Checker
+}
+
+func New(checker health.Checker) *Handler {
+	return &Handler{
+		checker: checker,
+	}
+}
+
+// LivenessHandler сообщает, что процесс жив
+// @Summary Liveness probe
+// @Description Always returns 200 while the process is able to serve HTTP. Dependencies are not checked.
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Router /healthz [get]
+func (h *Handler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, dto.HealthResponse{Status: dto.HealthStatusOK})
+}
+
+// ReadinessHandler проверяет зависимости сервиса
+// @Summary Readiness probe
+// @Description Checks Postgres, Kafka brokers and the Kafka consumer. Returns 503 if any check fails
+// @Description or the service is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Failure 503 {object} dto.HealthResponse
+// @Router /readyz [get]
+func (h *Handler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	resp := h.checker.Ready(r.Context())
+
+	status := http.StatusOK
+	if resp.Status != dto.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSONResponse(w, status, resp)
+}
+
+func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
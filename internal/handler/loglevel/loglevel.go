@@ -0,0 +1,90 @@
+package loglevel
+
+import (
+	"TaskService/internal/dto"
+	"TaskService/pkg/logger"
+	"encoding/json"
+	"net/http"
+)
+
+type Handler struct{}
+
+func New() *Handler {
+	return &Handler{}
+}
+
+// GetLogLevelHandler возвращает текущие уровни логирования
+// @Summary Get log level
+// @Description Get the current log level and per-logger overrides
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.LogLevelResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/log-level [get]
+func (h *Handler) GetLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, current())
+}
+
+// UpdateLogLevelHandler меняет уровни логирования без перезапуска
+// @Summary Update log level
+// @Description Change the log level at runtime. Overrides in "named" replace all current overrides;
+// @Description omit "named" to keep them. Levels are reset to the configuration on restart or SIGHUP.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateLogLevelRequest true "Log levels"
+// @Success 200 {object} dto.LogLevelResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/log-level [put]
+func (h *Handler) UpdateLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateLogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if _, err := logger.ParseLevel(req.Level); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid log level")
+		return
+	}
+
+	if req.Named != nil {
+		if err := logger.SetNamedLevels(req.Named); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid log level")
+			return
+		}
+	}
+
+	logger.SetLevel(req.Level)
+
+	resp := current()
+
+	log := logger.FromContext(r.Context())
+	log.Warn().Str("level", resp.Level).Interface("named", resp.Named).Msg("log level changed")
+
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+func current() dto.LogLevelResponse {
+	return dto.LogLevelResponse{
+		Level: logger.Level(),
+		Named: logger.NamedLevels(),
+	}
+}
+
+func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	writeJSONResponse(w, statusCode, dto.NewErrorResponse(message))
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"TaskService/internal/auth"
+	"net/http"
+)
+
+// RequireAdmin пропускает запросы администратора, см. auth.Principal.IsAdmin.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok || !principal.IsAdmin() {
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
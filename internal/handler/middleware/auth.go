@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/pkg/logger"
+	"net/http"
+)
+
+// Auth пропускает только аутентифицированные запросы и кладет Principal в контекст.
+func Auth(authenticator auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticator.Authenticate(r)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="task-service"`)
+				writeErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			ctx := auth.WithPrincipal(r.Context(), principal)
+
+			log := logger.FromContext(ctx).With().Str("subject", principal.Subject).Logger()
+			ctx = withLogger(ctx, log)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Trusted используется вместо Auth, когда аутентификация выключена: помечает запросы
+// доверенными, иначе сервис не дает доступа к задачам вызовам без Principal.
+func Trusted(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(auth.WithTrusted(r.Context())))
+	})
+}
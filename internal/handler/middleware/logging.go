@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"TaskService/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"regexp"
+	"time"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+var (
+	requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+	// quietPaths запросы проб и сборщика метрик, которые не пишутся в access log
+	quietPaths = map[string]struct{}{
+		"/healthz": {},
+		"/readyz":  {},
+		"/metrics": {},
+	}
+)
+
+type accessLoggerKey struct{}
+
+// accessLogger логгер запроса, который дополняют middleware после RequestLogger. Через него
+// access log получает поля, добавленные ниже по цепочке: subject, tenant_id.
+type accessLogger struct {
+	log zerolog.Logger
+}
+
+// withLogger кладет log в контекст запроса и в access log.
+func withLogger(ctx context.Context, log zerolog.Logger) context.Context {
+	if al, ok := ctx.Value(accessLoggerKey{}).(*accessLogger); ok {
+		al.log = log
+	}
+
+	return logger.WrapToContext(ctx, log)
+}
+
+// RequestLogger берет X-Request-ID запроса или назначает новый, возвращает его в ответе
+// и кладет в контекст логгер с request_id и trace_id. После обработки пишет access log.
+// Query string не логируется: в ней может быть access_token.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+
+		lctx := logger.FromContext(r.Context()).With().Str("request_id", requestID)
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			lctx = lctx.Str("trace_id", sc.TraceID().String())
+		}
+		al := &accessLogger{log: lctx.Logger()}
+		ctx := context.WithValue(r.Context(), accessLoggerKey{}, al)
+
+		ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(withLogger(ctx, al.log)))
+
+		if _, ok := quietPaths[r.URL.Path]; ok {
+			return
+		}
+
+		status := responseStatus(ww)
+
+		level := zerolog.InfoLevel
+		if status >= http.StatusInternalServerError {
+			level = zerolog.ErrorLevel
+		}
+
+		al.log.WithLevel(level).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("route", routePattern(r)).
+			Int("status", status).
+			Int("bytes", ww.BytesWritten()).
+			Dur("duration", time.Since(start)).
+			Str("remote_ip", remoteIP(r)).
+			Str("user_agent", r.UserAgent()).
+			Msg("http request")
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/pkg/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	r := chi.NewRouter()
+	r.Use(RequestLogger)
+	r.Get("/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		log := logger.FromContext(r.Context())
+		log.Info().Msg("handler")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	serve := func(requestID string) *httptest.ResponseRecorder {
+		buf.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/tasks/1?access_token=secret", nil)
+		req.Header.Set(RequestIDHeader, requestID)
+		req = req.WithContext(logger.WrapToContext(context.Background(), zerolog.New(&buf)))
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("propagates request ID", func(t *testing.T) {
+		rec := serve("req-1")
+		assert.Equal(t, "req-1", rec.Header().Get(RequestIDHeader))
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+
+		var handlerLog, accessLog map[string]interface{}
+		require.NoError(t, json.Unmarshal(lines[0], &handlerLog))
+		require.NoError(t, json.Unmarshal(lines[1], &accessLog))
+
+		assert.Equal(t, "req-1", handlerLog["request_id"])
+		assert.Equal(t, "req-1", accessLog["request_id"])
+		assert.Equal(t, "/tasks/{id}", accessLog["route"])
+		assert.Equal(t, float64(http.StatusNotFound), accessLog["status"])
+		assert.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("replaces invalid request ID", func(t *testing.T) {
+		rec := serve("bad id\n")
+		assert.Regexp(t, "^[0-9a-f]{32}$", rec.Header().Get(RequestIDHeader))
+	})
+}
+
+func TestRequestLogger_AccessLogFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	authenticator, err := auth.NewJWT(auth.Config{Secret: "secret"})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(RequestLogger)
+	r.Use(Auth(authenticator))
+	r.Use(Tenant())
+	r.Get("/tasks", func(w http.ResponseWriter, r *http.Request) {})
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-1", "tenant_id": "hr"}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req = req.WithContext(logger.WrapToContext(context.Background(), zerolog.New(&buf)))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var accessLog map[string]interface{}
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &accessLog))
+
+	assert.Equal(t, "http request", accessLog["message"])
+	assert.Equal(t, "user-1", accessLog["subject"])
+	assert.Equal(t, "hr", accessLog["tenant_id"])
+	assert.NotEmpty(t, accessLog["request_id"])
+}
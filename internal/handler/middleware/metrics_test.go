@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_RoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Metrics)
+	r.Get("/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	before := testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/tasks/{id}", "404"))
+
+	for _, path := range []string{"/tasks/1", "/tasks/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	after := testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/tasks/{id}", "404"))
+	assert.Equal(t, float64(2), after-before)
+}
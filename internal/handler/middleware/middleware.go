@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"TaskService/internal/dto"
+	"encoding/json"
+	"net/http"
+)
+
+func writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(dto.NewErrorResponse(message))
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/internal/ratelimit"
+	"TaskService/pkg/logger"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit ограничивает частоту запросов клиента: API-ключа или пользователя, иначе IP-адреса.
+// Чтение и запись лимитируются отдельно. При недоступности хранилища лимитов запрос пропускается.
+func RateLimit(limiter ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.Allow(r.Context(), clientKey(r), requestClass(r))
+			if err != nil {
+				log := logger.FromContext(r.Context())
+				log.Warn().Err(err).Msg("rate limit check failed")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+				writeErrorResponse(w, http.StatusTooManyRequests, "Too many requests")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientKey(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		return "sub:" + principal.Subject
+	}
+
+	return "ip:" + remoteIP(r)
+}
+
+func requestClass(r *http.Request) ratelimit.Class {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ratelimit.ClassRead
+	default:
+		return ratelimit.ClassWrite
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{
+		Read:  ratelimit.Limit{Rate: 1, Burst: 2},
+		Write: ratelimit.Limit{Rate: 0.5, Burst: 1},
+	}, ratelimit.NewMemoryStore())
+
+	h := RateLimit(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(method string, principal *auth.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/tasks", nil)
+		req.RemoteAddr = "10.0.0.1:5555"
+		if principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), *principal))
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := send(http.MethodPost, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Reset"))
+
+	rec = send(http.MethodPost, nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	// чтение и другой клиент с того же адреса лимитируются отдельно
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, nil).Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, &auth.Principal{Subject: "apikey:1"}).Code)
+}
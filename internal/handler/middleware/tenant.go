@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"TaskService/internal/tenant"
+	"TaskService/pkg/logger"
+	"errors"
+	"net/http"
+)
+
+// Tenant определяет тенант запроса по правилам tenant.Resolve.
+func Tenant() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := tenant.Resolve(r.Context(), r.Header.Get(tenant.Header))
+			switch {
+			case errors.Is(err, tenant.ErrInvalid):
+				writeErrorResponse(w, http.StatusBadRequest, "Invalid tenant")
+				return
+			case err != nil:
+				writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+
+			ctx := tenant.WithID(r.Context(), id)
+
+			log := logger.FromContext(ctx).With().Str("tenant_id", id).Logger()
+			ctx = withLogger(ctx, log)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/internal/tenant"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenant(t *testing.T) {
+	var resolved string
+	h := Tenant()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resolved = tenant.FromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		trusted   bool
+		header    string
+		code      int
+		tenant    string
+	}{
+		{name: "default", code: http.StatusOK, tenant: tenant.Default},
+		{name: "header without auth", trusted: true, header: "sales", code: http.StatusOK, tenant: "sales"},
+		{name: "header without principal", header: "sales", code: http.StatusForbidden},
+		{name: "invalid header", header: "sales/../hr", code: http.StatusBadRequest},
+		{name: "token tenant", principal: &auth.Principal{Subject: "u", Tenant: "hr"}, code: http.StatusOK, tenant: "hr"},
+		{name: "header mismatch", principal: &auth.Principal{Subject: "u", Tenant: "hr"}, header: "sales", code: http.StatusForbidden},
+		{name: "unbound non-admin", principal: &auth.Principal{Subject: "u"}, header: "sales", code: http.StatusForbidden},
+		{name: "unbound admin", principal: &auth.Principal{Subject: "u", Scopes: []string{auth.ScopeAdmin}}, header: "sales", code: http.StatusOK, tenant: "sales"},
+		{name: "unbound admin role", principal: &auth.Principal{Subject: "u", Claims: map[string]interface{}{"role": "admin"}}, header: "sales", code: http.StatusOK, tenant: "sales"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved = ""
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tt.principal))
+			}
+			if tt.trusted {
+				req = req.WithContext(auth.WithTrusted(req.Context()))
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.tenant, resolved)
+		})
+	}
+}
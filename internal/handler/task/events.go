@@ -0,0 +1,123 @@
+package task
+
+import (
+	"TaskService/internal/dto"
+	"TaskService/internal/service/event"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const eventsKeepAliveInterval = 15 * time.Second
+
+// TaskEventsHandler отдает поток изменений задач через Server-Sent Events
+// @Summary Stream task events
+// @Description Server-Sent Events stream of task create, update and status-change events. Resume with the Last-Event-ID header. If the event is no longer in history, the stream starts with a resync event and the client must reload tasks.
+// @Tags tasks
+// @Produce text/event-stream
+// @Param id query []int false "Filter by task ID" collectionFormat(multi)
+// @Param status query string false "Filter by task status"
+// @Param Last-Event-ID header string false "Resume after this event ID"
+// @Param X-Tenant-ID header string false "Tenant ID, defaults to the token tenant"
+// @Success 200 {object} dto.TaskEventResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/events [get]
+func (h *Handler) TaskEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorResponse(w, http.StatusInternalServerError, "Streaming unsupported")
+		return
+	}
+
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	sub, err := h.service.Task().Subscribe(r.Context(), filter, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-h.done:
+			return
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func parseEventFilter(r *http.Request) (event.Filter, error) {
+	query := r.URL.Query()
+
+	filter := event.Filter{
+		Status: query.Get("status"),
+	}
+
+	for _, v := range query["id"] {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, err
+		}
+
+		filter.TaskIDs = append(filter.TaskIDs, id)
+	}
+
+	return filter, nil
+}
+
+func writeEvent(w http.ResponseWriter, e event.Event) error {
+	data, err := json.Marshal(dto.NewTaskEventResponse(e))
+	if err != nil {
+		return err
+	}
+
+	// у resync нет ID: браузер переподключится с прежним Last-Event-ID и снова получит resync
+	if e.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", e.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+
+	return err
+}
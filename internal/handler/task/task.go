@@ -4,6 +4,7 @@ import (
 	"TaskService/internal/dto"
 	"TaskService/internal/service"
 	"TaskService/internal/service/task"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -14,11 +15,14 @@ import (
 
 type Handler struct {
 	service service.Service
+	// done закрывается при остановке сервера и завершает потоки событий
+	done <-chan struct{}
 }
 
-func New(service service.Service) *Handler {
+func New(service service.Service, done <-chan struct{}) *Handler {
 	return &Handler{
 		service: service,
+		done:    done,
 	}
 }
 
@@ -29,10 +33,15 @@ func New(service service.Service) *Handler {
 // @Accept json
 // @Produce json
 // @Param id path int true "Task ID"
+// @Param X-Tenant-ID header string false "Tenant ID, defaults to the token tenant"
 // @Success 200 {object} dto.GetTaskResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /tasks/{id} [get]
 func (h *Handler) GetTaskHandler(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -42,28 +51,56 @@ func (h *Handler) GetTaskHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.service.Task().Get(r.Context(), id)
+	result, err := h.service.Task().Get(r.Context(), id)
 	if err != nil {
+		if errors.Is(err, task.ErrForbidden) {
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+			return
+		}
 		writeErrorResponse(w, http.StatusNotFound, "Task not found")
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, task)
+	writeJSONResponse(w, http.StatusOK, result)
 }
 
-// GetTaskListHandler возвращает список всех задач
+// GetTaskListHandler возвращает список задач
 // @Summary Get all tasks
-// @Description Get list of all tasks
+// @Description Get list of tasks ordered by ID, optionally filtered by status or assignee and paginated.
+// @Description Non-admin users only see tasks they created or are assigned to.
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param status query string false "Filter by task status"
+// @Param assignee query string false "Filter by assignee, \"me\" for the current user"
+// @Param limit query int false "Page size, all tasks when omitted"
+// @Param offset query int false "Number of tasks to skip"
+// @Param X-Tenant-ID header string false "Tenant ID, defaults to the token tenant"
 // @Success 200 {object} dto.GetTaskListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /tasks [get]
 func (h *Handler) GetTaskListHandler(w http.ResponseWriter, r *http.Request) {
-	tasks, err := h.service.Task().GetList(r.Context())
+	req, err := parseListRequest(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid pagination parameters")
+		return
+	}
+
+	tasks, err := h.service.Task().GetList(r.Context(), req)
 	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "Failed to get tasks")
+		switch {
+		case errors.Is(err, task.ErrInvalidAssignee):
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid assignee")
+		case errors.Is(err, task.ErrForbidden):
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to get tasks")
+		}
 		return
 	}
 
@@ -77,9 +114,15 @@ func (h *Handler) GetTaskListHandler(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateTaskRequest true "Task creation data"
+// @Param X-Tenant-ID header string false "Tenant ID, defaults to the token tenant"
 // @Success 201 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse "Task quota of the tenant exceeded"
+// @Failure 429 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /tasks [post]
 func (h *Handler) CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreateTaskRequest
@@ -94,7 +137,16 @@ func (h *Handler) CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Task().Create(r.Context(), req); err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "Failed to create task")
+		switch {
+		case errors.Is(err, task.ErrForbidden):
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+		case errors.Is(err, task.ErrQuotaExceeded):
+			writeErrorResponse(w, http.StatusConflict, "Task quota exceeded")
+		case errors.Is(err, task.ErrInvalidAssignee):
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid assignee")
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to create task")
+		}
 		return
 	}
 
@@ -108,9 +160,15 @@ func (h *Handler) CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param request body dto.UpdateTaskRequest true "Task update data"
+// @Param X-Tenant-ID header string false "Tenant ID, defaults to the token tenant"
 // @Success 200 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
 // @Router /tasks [put]
 func (h *Handler) UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
 	var req dto.UpdateTaskRequest
@@ -131,17 +189,62 @@ func (h *Handler) UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.Task().Update(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, task.ErrInvalidStatus) {
+		switch {
+		case errors.Is(err, task.ErrInvalidStatus):
 			writeErrorResponse(w, http.StatusBadRequest, "Invalid status")
-			return
+		case errors.Is(err, task.ErrInvalidAssignee):
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid assignee")
+		case errors.Is(err, task.ErrForbidden):
+			writeErrorResponse(w, http.StatusForbidden, "Forbidden")
+		case errors.Is(err, sql.ErrNoRows):
+			writeErrorResponse(w, http.StatusNotFound, "Task not found")
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to update task")
 		}
-		writeErrorResponse(w, http.StatusInternalServerError, "Failed to update task")
 		return
 	}
 
 	writeJSONResponse(w, http.StatusOK, dto.NewSuccessResponse("Task updated successfully"))
 }
 
+func parseListRequest(r *http.Request) (dto.GetTaskListRequest, error) {
+	query := r.URL.Query()
+
+	req := dto.GetTaskListRequest{
+		Status:   query.Get("status"),
+		Assignee: query.Get("assignee"),
+	}
+
+	var err error
+
+	if req.Limit, err = parseNonNegative(query.Get("limit")); err != nil {
+		return req, err
+	}
+
+	if req.Offset, err = parseNonNegative(query.Get("offset")); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func parseNonNegative(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 {
+		return 0, errors.New("negative value")
+	}
+
+	return n, nil
+}
+
 func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
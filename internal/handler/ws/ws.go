@@ -0,0 +1,302 @@
+package ws
+
+import (
+	"TaskService/internal/dto"
+	"TaskService/internal/service"
+	"TaskService/internal/service/event"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4096
+	sendBufferSize = 64
+)
+
+type Handler struct {
+	service  service.Service
+	upgrader websocket.Upgrader
+	// shutdown закрывается при остановке сервера, соединения закрываются с кодом 1001
+	shutdown <-chan struct{}
+}
+
+func New(service service.Service, shutdown <-chan struct{}) *Handler {
+	return &Handler{
+		service:  service,
+		shutdown: shutdown,
+	}
+}
+
+// SubscribeHandler открывает WebSocket-соединение для подписки на изменения задач
+// @Summary Subscribe to live task updates
+// @Description WebSocket endpoint. Send dto.WSClientMessage to subscribe/unsubscribe, receive a snapshot followed by deltas as dto.WSServerMessage.
+// @Tags tasks
+// @Param X-Tenant-ID header string false "Tenant ID, defaults to the token tenant"
+// @Success 101 {object} dto.WSServerMessage
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /ws [get]
+func (h *Handler) SubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	// соединение живёт дольше обработчика, но сохраняет значения запроса (субъект)
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+
+	c := &client{
+		conn:     conn,
+		service:  h.service,
+		send:     make(chan dto.WSServerMessage, sendBufferSize),
+		done:     make(chan struct{}),
+		shutdown: h.shutdown,
+		cancel:   cancel,
+		subs:     make(map[string]event.Subscription),
+	}
+
+	go c.writePump()
+	c.readPump(ctx)
+}
+
+type client struct {
+	conn     *websocket.Conn
+	service  service.Service
+	send     chan dto.WSServerMessage
+	done     chan struct{}
+	shutdown <-chan struct{}
+	cancel   context.CancelFunc
+
+	closeOnce sync.Once
+
+	mu   sync.Mutex
+	subs map[string]event.Subscription
+}
+
+func (c *client) readPump(ctx context.Context) {
+	defer c.close()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg dto.WSClientMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case dto.WSTypeSubscribe:
+			c.subscribe(ctx, msg)
+		case dto.WSTypeUnsubscribe:
+			c.unsubscribe(msg.ID)
+		default:
+			c.enqueue(errorMessage(msg.ID, "unknown message type"))
+		}
+	}
+}
+
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	defer c.close()
+
+	for {
+		select {
+		case <-c.done:
+			return
+
+		case <-c.shutdown:
+			_ = c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+				time.Now().Add(writeWait))
+			return
+
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *client) subscribe(ctx context.Context, msg dto.WSClientMessage) {
+	if msg.ID == "" {
+		c.enqueue(errorMessage("", "subscription id is required"))
+		return
+	}
+
+	c.unsubscribe(msg.ID)
+
+	filter := event.Filter{
+		TaskIDs: msg.TaskIDs,
+		Status:  msg.Status,
+	}
+
+	// Подписываемся до снимка, чтобы не потерять изменения между ними.
+	sub, err := c.service.Task().Subscribe(ctx, filter, "")
+	if err != nil {
+		c.enqueue(errorMessage(msg.ID, "forbidden"))
+		return
+	}
+
+	c.mu.Lock()
+	select {
+	case <-c.done:
+		c.mu.Unlock()
+		sub.Close()
+		return
+	default:
+		c.subs[msg.ID] = sub
+	}
+	c.mu.Unlock()
+
+	tasks, err := c.snapshot(ctx, filter)
+	if err != nil {
+		c.unsubscribe(msg.ID)
+		c.enqueue(errorMessage(msg.ID, "failed to get tasks"))
+		return
+	}
+
+	c.enqueue(dto.WSServerMessage{
+		Type:         dto.WSTypeSnapshot,
+		Subscription: msg.ID,
+		Tasks:        tasks,
+	})
+
+	go c.forward(msg.ID, sub)
+}
+
+func (c *client) unsubscribe(id string) {
+	c.mu.Lock()
+	sub, ok := c.subs[id]
+	delete(c.subs, id)
+	c.mu.Unlock()
+
+	if ok {
+		sub.Close()
+	}
+}
+
+func (c *client) forward(id string, sub event.Subscription) {
+	for e := range sub.Events() {
+		resp := dto.NewTaskEventResponse(e)
+
+		if !c.enqueue(dto.WSServerMessage{Type: dto.WSTypeEvent, Subscription: id, Event: &resp}) {
+			return
+		}
+	}
+
+	// Шина закрыла подписку, которую клиент не отменял: он не успевает читать.
+	c.mu.Lock()
+	current, ok := c.subs[id]
+	c.mu.Unlock()
+
+	if ok && current == sub {
+		c.drop()
+	}
+}
+
+func (c *client) snapshot(ctx context.Context, filter event.Filter) ([]dto.GetTaskResponse, error) {
+	tasks := make([]dto.GetTaskResponse, 0)
+
+	if len(filter.TaskIDs) > 0 {
+		for _, id := range filter.TaskIDs {
+			task, err := c.service.Task().Get(ctx, id)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if filter.Status == "" || filter.Status == task.Status {
+				tasks = append(tasks, task)
+			}
+		}
+
+		return tasks, nil
+	}
+
+	list, err := c.service.Task().GetList(ctx, dto.GetTaskListRequest{Status: filter.Status})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tasks, list.Tasks...), nil
+}
+
+// enqueue не блокируется: клиент с переполненным буфером отключается.
+func (c *client) enqueue(msg dto.WSServerMessage) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	case <-c.done:
+		return false
+	default:
+		c.drop()
+		return false
+	}
+}
+
+func (c *client) drop() {
+	_ = c.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"),
+		time.Now().Add(writeWait),
+	)
+
+	c.close()
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.cancel()
+		_ = c.conn.Close()
+
+		c.mu.Lock()
+		subs := c.subs
+		c.subs = make(map[string]event.Subscription)
+		c.mu.Unlock()
+
+		for _, sub := range subs {
+			sub.Close()
+		}
+	})
+}
+
+func errorMessage(id string, message string) dto.WSServerMessage {
+	return dto.WSServerMessage{
+		Type:         dto.WSTypeError,
+		Subscription: id,
+		Error:        message,
+	}
+}
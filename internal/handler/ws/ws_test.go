@@ -0,0 +1,66 @@
+package ws
+
+import (
+	"TaskService/internal/dto"
+	"TaskService/internal/model"
+	"TaskService/internal/service/event"
+	"TaskService/internal/service/servicetest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeHandler_SnapshotThenDeltas(t *testing.T) {
+	srv := servicetest.NewMemory(model.Task{ID: 1, Title: "Task 1", Status: "created"})
+
+	server := httptest.NewServer(http.HandlerFunc(New(srv, nil).SubscribeHandler))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(dto.WSClientMessage{Type: dto.WSTypeSubscribe, ID: "s1", TaskIDs: []int{1}}))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var snapshot dto.WSServerMessage
+	require.NoError(t, conn.ReadJSON(&snapshot))
+	assert.Equal(t, dto.WSTypeSnapshot, snapshot.Type)
+	assert.Equal(t, "s1", snapshot.Subscription)
+	require.Len(t, snapshot.Tasks, 1)
+	assert.Equal(t, "Task 1", snapshot.Tasks[0].Title)
+
+	srv.Publish(event.TypeCreated, model.Task{ID: 2})
+	srv.Publish(event.TypeUpdated, model.Task{ID: 1, Title: "Task 1", Status: "done"})
+
+	var delta dto.WSServerMessage
+	require.NoError(t, conn.ReadJSON(&delta))
+	assert.Equal(t, dto.WSTypeEvent, delta.Type)
+	require.NotNil(t, delta.Event)
+	assert.Equal(t, string(event.TypeUpdated), delta.Event.Type)
+	assert.Equal(t, "done", delta.Event.Task.Status)
+}
+
+func TestSubscribeHandler_UnknownMessage(t *testing.T) {
+	srv := servicetest.NewMemory()
+
+	server := httptest.NewServer(http.HandlerFunc(New(srv, nil).SubscribeHandler))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(dto.WSClientMessage{Type: "noop"}))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg dto.WSServerMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, dto.WSTypeError, msg.Type)
+}
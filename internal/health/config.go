@@ -0,0 +1,25 @@
+package health
+
+import "time"
+
+const defaultTimeout = 2 * time.Second
+
+type Config struct {
+	// Timeout ограничивает время одной проверки readiness.
+	Timeout time.Duration
+	// ShutdownDelay пауза между переводом readiness в fail и остановкой сервера,
+	// чтобы балансировщик успел вывести реплику из ротации.
+	ShutdownDelay time.Duration
+}
+
+func validateConfig(cfg Config) Config {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	if cfg.ShutdownDelay < 0 {
+		cfg.ShutdownDelay = 0
+	}
+
+	return cfg
+}
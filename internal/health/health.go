@@ -0,0 +1,107 @@
+package health
+
+import (
+	"TaskService/internal/dto"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrShuttingDown = errors.New("shutting down")
+
+// Check проверяет доступность зависимости.
+type Check func(ctx context.Context) error
+
+type Checker interface {
+	// Ready выполняет проверки параллельно и возвращает результат по каждой зависимости.
+	Ready(ctx context.Context) dto.HealthResponse
+	// Shutdown переводит readiness в fail до конца работы процесса.
+	Shutdown()
+	// ShutdownDelay время, которое нужно подождать после Shutdown перед остановкой сервера.
+	ShutdownDelay() time.Duration
+}
+
+type checker struct {
+	cfg          Config
+	checks       map[string]Check
+	shuttingDown atomic.Bool
+}
+
+func New(cfg Config, checks map[string]Check) Checker {
+	result := &checker{
+		cfg:    validateConfig(cfg),
+		checks: checks,
+	}
+
+	return result
+}
+
+func (c *checker) Ready(ctx context.Context) dto.HealthResponse {
+	result := dto.HealthResponse{
+		Status: dto.HealthStatusOK,
+		Checks: make(map[string]dto.HealthCheck, len(c.checks)+1),
+	}
+
+	if c.shuttingDown.Load() {
+		result.Status = dto.HealthStatusFail
+		result.Checks["shutdown"] = dto.HealthCheck{Status: dto.HealthStatusFail, Error: ErrShuttingDown.Error()}
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for name, check := range c.checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			result.Checks[name] = res
+			if res.Status != dto.HealthStatusOK {
+				result.Status = dto.HealthStatusFail
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return result
+}
+
+func (c *checker) run(ctx context.Context, check Check) dto.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	err := check(ctx)
+
+	result := dto.HealthCheck{
+		Status:     dto.HealthStatusOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		result.Status = dto.HealthStatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (c *checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *checker) ShutdownDelay() time.Duration {
+	return c.cfg.ShutdownDelay
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"TaskService/internal/dto"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	checker := New(Config{}, map[string]Check{
+		"postgres": func(context.Context) error { return nil },
+		"kafka":    func(context.Context) error { return errors.New("no brokers") },
+	})
+
+	resp := checker.Ready(context.Background())
+
+	assert.Equal(t, dto.HealthStatusFail, resp.Status)
+	assert.Equal(t, dto.HealthStatusOK, resp.Checks["postgres"].Status)
+	assert.Equal(t, dto.HealthCheck{Status: dto.HealthStatusFail, Error: "no brokers"}, resp.Checks["kafka"])
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	checker := New(Config{Timeout: 10 * time.Millisecond}, map[string]Check{
+		"postgres": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	resp := checker.Ready(context.Background())
+
+	assert.Equal(t, dto.HealthStatusFail, resp.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), resp.Checks["postgres"].Error)
+}
+
+func TestChecker_Shutdown(t *testing.T) {
+	checker := New(Config{ShutdownDelay: time.Second}, map[string]Check{
+		"postgres": func(context.Context) error { return nil },
+	})
+
+	assert.Equal(t, dto.HealthStatusOK, checker.Ready(context.Background()).Status)
+
+	checker.Shutdown()
+
+	resp := checker.Ready(context.Background())
+	assert.Equal(t, dto.HealthStatusFail, resp.Status)
+	assert.Equal(t, ErrShuttingDown.Error(), resp.Checks["shutdown"].Error)
+	assert.Equal(t, time.Second, checker.ShutdownDelay())
+}
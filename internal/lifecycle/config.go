@@ -0,0 +1,18 @@
+package lifecycle
+
+import "time"
+
+const defaultTimeout = 30 * time.Second
+
+type Config struct {
+	// Timeout ограничивает всю остановку: ожидание запросов, обработки задач и закрытие соединений.
+	Timeout time.Duration
+}
+
+func validateConfig(cfg Config) Config {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return cfg
+}
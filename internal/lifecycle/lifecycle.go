@@ -0,0 +1,79 @@
+package lifecycle
+
+import (
+	"TaskService/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StopFunc останавливает компонент. ctx отменяется по истечении общего таймаута остановки.
+type StopFunc func(ctx context.Context) error
+
+type Manager interface {
+	// Add регистрирует компонент. Компоненты останавливаются в обратном порядке регистрации:
+	// первыми — зарегистрированные последними, как defer.
+	Add(name string, stop StopFunc)
+	// Shutdown останавливает все компоненты, даже если часть из них вернула ошибку
+	// или таймаут истек. Повторные вызовы ничего не делают.
+	Shutdown(ctx context.Context) error
+}
+
+type component struct {
+	name string
+	stop StopFunc
+}
+
+type manager struct {
+	cfg        Config
+	mu         sync.Mutex
+	components []component
+	once       sync.Once
+}
+
+func New(cfg Config) Manager {
+	result := &manager{
+		cfg: validateConfig(cfg),
+	}
+
+	return result
+}
+
+func (m *manager) Add(name string, stop StopFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.components = append(m.components, component{name: name, stop: stop})
+}
+
+func (m *manager) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	m.once.Do(func() {
+		ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+		defer cancel()
+
+		m.mu.Lock()
+		components := m.components
+		m.mu.Unlock()
+
+		log := logger.FromContext(ctx)
+
+		for i := len(components) - 1; i >= 0; i-- {
+			c := components[i]
+			start := time.Now()
+
+			if err := c.stop(ctx); err != nil {
+				log.Error().Err(err).Str("component", c.name).Msg("stop failed")
+				errs = append(errs, fmt.Errorf("stop %s: %w", c.name, err))
+				continue
+			}
+
+			log.Info().Str("component", c.name).Dur("duration", time.Since(start)).Msg("stopped")
+		}
+	})
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	m := New(Config{})
+
+	var order []string
+
+	stop := func(name string, err error) StopFunc {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+
+	m.Add("postgres", stop("postgres", nil))
+	m.Add("kafka", stop("kafka", errors.New("close failed")))
+	m.Add("http", stop("http", nil))
+
+	err := m.Shutdown(context.Background())
+
+	assert.EqualError(t, err, "stop kafka: close failed")
+	assert.Equal(t, []string{"http", "kafka", "postgres"}, order)
+
+	assert.NoError(t, m.Shutdown(context.Background()))
+	assert.Len(t, order, 3)
+}
+
+func TestManager_Timeout(t *testing.T) {
+	m := New(Config{Timeout: 20 * time.Millisecond})
+
+	closed := false
+
+	m.Add("postgres", func(context.Context) error {
+		closed = true
+		return nil
+	})
+	m.Add("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := m.Shutdown(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, closed)
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type APIKey struct {
+	ID         int
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	TenantID   string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
@@ -5,4 +5,16 @@ type Task struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
+	CreatedBy   string `json:"created_by" db:"created_by"`
+	Assignee    string `json:"assignee" db:"assignee"`
+	TenantID    string `json:"tenant_id" db:"tenant_id"`
+}
+
+type TaskFilter struct {
+	Status   string
+	Assignee string
+	// VisibleTo оставляет только задачи, созданные субъектом или назначенные на него.
+	VisibleTo string
+	Limit     int
+	Offset    int
 }
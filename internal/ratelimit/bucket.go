@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Bucket состояние token bucket на момент Updated.
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Result решение по запросу для заголовков RateLimit-*.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset время до полного восстановления бакета.
+	Reset time.Duration
+	// RetryAfter время до появления следующего токена, если запрос отклонен.
+	RetryAfter time.Duration
+}
+
+// NewBucket возвращает полный бакет.
+func NewBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(limit.Burst), Updated: now}
+}
+
+// Take пополняет бакет за прошедшее время и списывает токен, если он есть.
+// Время бакета не идет назад, даже если now раньше Updated.
+func Take(b Bucket, limit Limit, now time.Time) (Bucket, Result) {
+	if now.Before(b.Updated) {
+		now = b.Updated
+	}
+
+	elapsed := now.Sub(b.Updated).Seconds()
+
+	tokens := math.Min(float64(limit.Burst), b.Tokens+elapsed*limit.Rate)
+
+	result := Result{
+		Limit: limit.Burst,
+	}
+
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((float64(limit.Burst) - tokens) / limit.Rate)
+
+	return Bucket{Tokens: tokens, Updated: now}, result
+}
+
+func seconds(v float64) time.Duration {
+	return time.Duration(v * float64(time.Second))
+}
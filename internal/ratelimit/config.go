@@ -0,0 +1,47 @@
+package ratelimit
+
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+
+	defaultReadRate   = 10
+	defaultReadBurst  = 20
+	defaultWriteRate  = 2
+	defaultWriteBurst = 5
+)
+
+// Limit параметры token bucket: Rate токенов в секунду, не больше Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Config struct {
+	Enabled bool
+	// Backend хранилище бакетов: memory (на реплику) или postgres (общее для всех реплик).
+	Backend string
+	Read    Limit
+	Write   Limit
+}
+
+func validateConfig(cfg Config) Config {
+	if cfg.Backend == "" {
+		cfg.Backend = BackendMemory
+	}
+
+	if cfg.Read.Rate <= 0 {
+		cfg.Read.Rate = defaultReadRate
+	}
+	if cfg.Read.Burst <= 0 {
+		cfg.Read.Burst = defaultReadBurst
+	}
+
+	if cfg.Write.Rate <= 0 {
+		cfg.Write.Rate = defaultWriteRate
+	}
+	if cfg.Write.Burst <= 0 {
+		cfg.Write.Burst = defaultWriteBurst
+	}
+
+	return cfg
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	Bucket
+	limit Limit
+}
+
+// NewMemoryStore хранит бакеты в памяти процесса: лимиты действуют отдельно на каждой реплике.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		buckets: make(map[string]memoryBucket),
+	}
+}
+
+func (s *memoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = memoryBucket{Bucket: NewBucket(limit, now), limit: limit}
+	}
+
+	bucket, result := Take(b.Bucket, limit, now)
+	s.buckets[key] = memoryBucket{Bucket: bucket, limit: limit}
+
+	return result, nil
+}
+
+// sweep удаляет бакеты, которые уже восстановились полностью: они не отличаются от новых.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		full := b.Tokens + now.Sub(b.Updated).Seconds()*b.limit.Rate
+		if full >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type Class string
+
+const (
+	ClassRead  Class = "read"
+	ClassWrite Class = "write"
+)
+
+// Store хранит бакеты и атомарно списывает токены.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, class Class) (Result, error)
+	// SetLimits меняет лимиты на лету. Бакеты сохраняются и пересчитываются по новым лимитам
+	// при следующем запросе; Enabled и Backend не меняются.
+	SetLimits(read, write Limit)
+}
+
+type limiter struct {
+	cfg   atomic.Pointer[Config]
+	store Store
+	now   func() time.Time
+}
+
+func New(cfg Config, store Store) Limiter {
+	cfg = validateConfig(cfg)
+
+	result := &limiter{
+		store: store,
+		now:   time.Now,
+	}
+	result.cfg.Store(&cfg)
+
+	return result
+}
+
+func (l *limiter) SetLimits(read, write Limit) {
+	cfg := *l.cfg.Load()
+	cfg.Read = read
+	cfg.Write = write
+	cfg = validateConfig(cfg)
+
+	l.cfg.Store(&cfg)
+}
+
+func (l *limiter) Allow(ctx context.Context, key string, class Class) (Result, error) {
+	cfg := l.cfg.Load()
+
+	limit := cfg.Read
+	if class == ClassWrite {
+		limit = cfg.Write
+	}
+
+	return l.store.Take(ctx, string(class)+":"+key, limit, l.now())
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTake(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+
+	b := NewBucket(limit, now)
+
+	b, res := Take(b, limit, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	b, res = Take(b, limit, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 2*time.Second, res.Reset)
+
+	b, res = Take(b, limit, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	// время раньше Updated не пополняет бакет и не сдвигает его назад
+	b, res = Take(b, limit, now.Add(-time.Minute))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, now, b.Updated)
+
+	_, res = Take(b, limit, now.Add(time.Second))
+	assert.True(t, res.Allowed)
+}
+
+func TestLimiter_SeparateClasses(t *testing.T) {
+	l := New(Config{
+		Read:  Limit{Rate: 1, Burst: 1},
+		Write: Limit{Rate: 1, Burst: 1},
+	}, NewMemoryStore())
+
+	ctx := context.Background()
+
+	res, err := l.Allow(ctx, "ip:127.0.0.1", ClassWrite)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = l.Allow(ctx, "ip:127.0.0.1", ClassWrite)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	res, err = l.Allow(ctx, "ip:127.0.0.1", ClassRead)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = l.Allow(ctx, "ip:127.0.0.2", ClassWrite)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestLimiter_SetLimits(t *testing.T) {
+	l := New(Config{
+		Read:  Limit{Rate: 1, Burst: 1},
+		Write: Limit{Rate: 1, Burst: 1},
+	}, NewMemoryStore())
+
+	ctx := context.Background()
+
+	res, err := l.Allow(ctx, "ip:127.0.0.1", ClassRead)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = l.Allow(ctx, "ip:127.0.0.1", ClassRead)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	l.SetLimits(Limit{Rate: 1000, Burst: 5}, Limit{Rate: 1, Burst: 1})
+
+	time.Sleep(5 * time.Millisecond)
+
+	res, err = l.Allow(ctx, "ip:127.0.0.1", ClassRead)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 5, res.Limit)
+}
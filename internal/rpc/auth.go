@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/pkg/logger"
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authMetadata метаданные с учетными данными в том же формате, что и заголовок Authorization:
+// "Bearer <token>" или "ApiKey <key>".
+const authMetadata = "authorization"
+
+// authenticate проверяет учетные данные вызова аутентификатором HTTP API и кладет Principal в контекст.
+func authenticate(ctx context.Context, authenticator auth.Authenticator) (context.Context, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authMetadata); len(values) > 0 {
+			r.Header.Set("Authorization", values[0])
+		}
+	}
+
+	principal, err := authenticator.Authenticate(r)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+
+	ctx = auth.WithPrincipal(ctx, principal)
+
+	log := logger.FromContext(ctx).With().Str("subject", principal.Subject).Logger()
+
+	return logger.WrapToContext(ctx, log), nil
+}
+
+// public вызовы без аутентификации: проверки состояния для оркестраторов и балансировщиков.
+func public(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+func unaryAuthInterceptor(authenticator auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if public(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthInterceptor(authenticator auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if public(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// unaryTrustedInterceptor и streamTrustedInterceptor помечают вызовы доверенными, когда
+// аутентификация выключена, как middleware.Trusted для HTTP.
+func unaryTrustedInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(auth.WithTrusted(ctx), req)
+}
+
+func streamTrustedInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: ss, ctx: auth.WithTrusted(ss.Context())})
+}
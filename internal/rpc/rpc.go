@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	taskv1 "TaskService/api/task/v1"
+	"TaskService/internal/auth"
+	"TaskService/internal/service"
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+	done   chan struct{}
+}
+
+// New создает gRPC-сервер. authenticator проверяет метаданные authorization всех вызовов,
+// кроме health-check; nil отключает аутентификацию, как и для HTTP API.
+func New(srv service.Service, authenticator auth.Authenticator) *Server {
+	var (
+		unary  []grpc.UnaryServerInterceptor
+		stream []grpc.StreamServerInterceptor
+	)
+
+	if authenticator != nil {
+		unary = append(unary, unaryAuthInterceptor(authenticator))
+		stream = append(stream, streamAuthInterceptor(authenticator))
+	} else {
+		unary = append(unary, unaryTrustedInterceptor)
+		stream = append(stream, streamTrustedInterceptor)
+	}
+
+	unary = append(unary, unaryTenantInterceptor)
+	stream = append(stream, streamTenantInterceptor)
+
+	result := &Server{
+		grpc: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(unary...),
+			grpc.ChainStreamInterceptor(stream...),
+		),
+		health: health.NewServer(),
+		done:   make(chan struct{}),
+	}
+
+	taskv1.RegisterTaskServiceServer(result.grpc, newTaskServer(srv, result.done))
+	healthpb.RegisterHealthServer(result.grpc, result.health)
+	reflection.Register(result.grpc)
+
+	result.health.SetServingStatus(taskv1.TaskService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	return result
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown переводит health-check в NOT_SERVING, завершает Watch-стримы
+// и дожидается окончания остальных вызовов. По отмене ctx оставшиеся вызовы прерываются.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+	close(s.done)
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
+
+// contextStream подменяет контекст стрима, дополненный перехватчиками.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
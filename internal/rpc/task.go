@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	taskv1 "TaskService/api/task/v1"
+	"TaskService/internal/dto"
+	"TaskService/internal/service"
+	"TaskService/internal/service/event"
+	"TaskService/internal/service/task"
+	"context"
+	"database/sql"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type taskServer struct {
+	taskv1.UnimplementedTaskServiceServer
+
+	service service.Service
+	done    <-chan struct{}
+}
+
+func newTaskServer(service service.Service, done <-chan struct{}) *taskServer {
+	return &taskServer{
+		service: service,
+		done:    done,
+	}
+}
+
+func (s *taskServer) Get(ctx context.Context, req *taskv1.GetRequest) (*taskv1.Task, error) {
+	resp, err := s.service.Task().Get(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toProto(resp), nil
+}
+
+func (s *taskServer) List(ctx context.Context, req *taskv1.ListRequest) (*taskv1.ListResponse, error) {
+	if req.GetLimit() < 0 || req.GetOffset() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid pagination parameters")
+	}
+
+	resp, err := s.service.Task().GetList(ctx, dto.GetTaskListRequest{
+		Status:   req.GetStatus(),
+		Assignee: req.GetAssignee(),
+		Limit:    int(req.GetLimit()),
+		Offset:   int(req.GetOffset()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	result := &taskv1.ListResponse{
+		Tasks: make([]*taskv1.Task, 0, len(resp.Tasks)),
+	}
+
+	for _, t := range resp.Tasks {
+		result.Tasks = append(result.Tasks, toProto(t))
+	}
+
+	return result, nil
+}
+
+func (s *taskServer) Create(ctx context.Context, req *taskv1.CreateRequest) (*taskv1.CreateResponse, error) {
+	if req.GetTitle() == "" {
+		return nil, status.Error(codes.InvalidArgument, "title is required")
+	}
+
+	err := s.service.Task().Create(ctx, dto.CreateTaskRequest{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Assignee:    req.GetAssignee(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &taskv1.CreateResponse{}, nil
+}
+
+func (s *taskServer) Update(ctx context.Context, req *taskv1.UpdateRequest) (*taskv1.UpdateResponse, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "task id is required")
+	}
+
+	if req.GetTitle() == "" {
+		return nil, status.Error(codes.InvalidArgument, "title is required")
+	}
+
+	err := s.service.Task().Update(ctx, dto.UpdateTaskRequest{
+		ID:          int(req.GetId()),
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Status:      req.GetStatus(),
+		Assignee:    req.Assignee,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &taskv1.UpdateResponse{}, nil
+}
+
+func (s *taskServer) Watch(req *taskv1.WatchRequest, stream taskv1.TaskService_WatchServer) error {
+	filter := event.Filter{
+		Status: req.GetStatus(),
+	}
+
+	for _, id := range req.GetTaskIds() {
+		filter.TaskIDs = append(filter.TaskIDs, int(id))
+	}
+
+	sub, err := s.service.Task().Subscribe(stream.Context(), filter, req.GetLastEventId())
+	if err != nil {
+		return toStatus(err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+
+		case e, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "client too slow")
+			}
+
+			resp := &taskv1.TaskEvent{
+				Id:   e.ID,
+				Type: string(e.Type),
+				Time: timestamppb.New(e.Time),
+			}
+			if e.Type != event.TypeResync {
+				resp.Task = toProto(dto.NewGetTaskResponse(e.Task))
+			}
+
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toProto(t dto.GetTaskResponse) *taskv1.Task {
+	return &taskv1.Task{
+		Id:          int64(t.ID),
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		CreatedBy:   t.CreatedBy,
+		Assignee:    t.Assignee,
+	}
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "task not found")
+	case errors.Is(err, task.ErrInvalidStatus):
+		return status.Error(codes.InvalidArgument, "invalid status")
+	case errors.Is(err, task.ErrInvalidAssignee):
+		return status.Error(codes.InvalidArgument, "invalid assignee")
+	case errors.Is(err, task.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "forbidden")
+	case errors.Is(err, task.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, "task quota exceeded")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,220 @@
+package rpc
+
+import (
+	taskv1 "TaskService/api/task/v1"
+	"TaskService/internal/auth"
+	"TaskService/internal/model"
+	"TaskService/internal/service"
+	"TaskService/internal/service/event"
+	"TaskService/internal/service/servicetest"
+	"TaskService/internal/service/task"
+	"TaskService/internal/tenant"
+	"context"
+	"database/sql"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// tokenAuthenticator принимает единственный токен "Bearer secret".
+type tokenAuthenticator struct{}
+
+func (tokenAuthenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	switch r.Header.Get("Authorization") {
+	case "":
+		return auth.Principal{}, auth.ErrMissingCredentials
+	case "Bearer secret":
+		return auth.Principal{Subject: "alice"}, nil
+	case "Bearer hr":
+		return auth.Principal{Subject: "bob", Tenant: "hr"}, nil
+	case "Bearer admin":
+		return auth.Principal{Subject: "root", Scopes: []string{auth.ScopeAdmin}}, nil
+	default:
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+}
+
+func setupServer(t *testing.T, svc service.Service, authenticator auth.Authenticator) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := New(svc, authenticator)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestTaskServer_Get(t *testing.T) {
+	conn := setupServer(t, servicetest.NewMemory(model.Task{ID: 1, Title: "Task 1", Status: "created"}), nil)
+	client := taskv1.NewTaskServiceClient(conn)
+
+	resp, err := client.Get(context.Background(), &taskv1.GetRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Task 1", resp.GetTitle())
+
+	_, err = client.Get(context.Background(), &taskv1.GetRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTaskServer_Watch(t *testing.T) {
+	srv := servicetest.NewMemory()
+	created := srv.Publish(event.TypeCreated, model.Task{ID: 1, Status: "created"})
+	srv.Publish(event.TypeCreated, model.Task{ID: 2, Status: "created"})
+	updated := srv.Publish(event.TypeUpdated, model.Task{ID: 1, Status: "done"})
+
+	conn := setupServer(t, srv, nil)
+	client := taskv1.NewTaskServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &taskv1.WatchRequest{TaskIds: []int64{1}, LastEventId: created.ID})
+	require.NoError(t, err)
+
+	e, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, updated.ID, e.GetId())
+	assert.Equal(t, string(event.TypeUpdated), e.GetType())
+	assert.Equal(t, "done", e.GetTask().GetStatus())
+}
+
+func TestTaskServer_WatchResync(t *testing.T) {
+	conn := setupServer(t, servicetest.NewMemory(), nil)
+	client := taskv1.NewTaskServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &taskv1.WatchRequest{LastEventId: "unknown"})
+	require.NoError(t, err)
+
+	e, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, string(event.TypeResync), e.GetType())
+	assert.Empty(t, e.GetId())
+	assert.Nil(t, e.GetTask())
+}
+
+func TestServer_Health(t *testing.T) {
+	conn := setupServer(t, servicetest.NewMemory(), tokenAuthenticator{})
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: taskv1.TaskService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+func TestServer_Auth(t *testing.T) {
+	ts := servicetest.NewMemory(model.Task{ID: 1, Title: "Task 1", Status: "created"})
+
+	conn := setupServer(t, ts, tokenAuthenticator{})
+	client := taskv1.NewTaskServiceClient(conn)
+
+	tests := []struct {
+		name string
+		md   []string
+		code codes.Code
+	}{
+		{name: "missing", code: codes.Unauthenticated},
+		{name: "invalid", md: []string{"authorization", "Bearer wrong"}, code: codes.Unauthenticated},
+		{name: "valid", md: []string{"authorization", "Bearer secret"}, code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), tt.md...))
+			defer cancel()
+
+			_, err := client.Get(ctx, &taskv1.GetRequest{Id: 1})
+			assert.Equal(t, tt.code, status.Code(err))
+
+			stream, err := client.Watch(ctx, &taskv1.WatchRequest{})
+			require.NoError(t, err)
+
+			if tt.code != codes.OK {
+				_, err = stream.Recv()
+				assert.Equal(t, tt.code, status.Code(err))
+			}
+		})
+	}
+
+	subject, _ := ts.LastGet()
+	assert.Equal(t, "alice", subject)
+}
+
+func TestServer_Tenant(t *testing.T) {
+	ts := servicetest.NewMemory(model.Task{ID: 1, Title: "Task 1", Status: "created"})
+
+	client := taskv1.NewTaskServiceClient(setupServer(t, ts, tokenAuthenticator{}))
+	open := taskv1.NewTaskServiceClient(setupServer(t, ts, nil))
+
+	tests := []struct {
+		name   string
+		client taskv1.TaskServiceClient
+		md     []string
+		code   codes.Code
+		tenant string
+	}{
+		{name: "token tenant", client: client, md: []string{"authorization", "Bearer hr"}, tenant: "hr"},
+		{name: "header matches token", client: client, md: []string{"authorization", "Bearer hr", "x-tenant-id", "hr"}, tenant: "hr"},
+		{name: "header mismatch", client: client, md: []string{"authorization", "Bearer hr", "x-tenant-id", "sales"}, code: codes.PermissionDenied},
+		{name: "unbound non-admin", client: client, md: []string{"authorization", "Bearer secret", "x-tenant-id", "sales"}, code: codes.PermissionDenied},
+		{name: "unbound default", client: client, md: []string{"authorization", "Bearer secret"}, tenant: tenant.Default},
+		{name: "unbound admin", client: client, md: []string{"authorization", "Bearer admin", "x-tenant-id", "sales"}, tenant: "sales"},
+		{name: "invalid", client: client, md: []string{"authorization", "Bearer admin", "x-tenant-id", "sales/hr"}, code: codes.InvalidArgument},
+		{name: "auth disabled", client: open, md: []string{"x-tenant-id", "sales"}, tenant: "sales"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), tt.md...)
+
+			_, err := tt.client.Get(ctx, &taskv1.GetRequest{Id: 1})
+			assert.Equal(t, tt.code, status.Code(err))
+
+			_, tenantID := ts.LastGet()
+			assert.Equal(t, tt.tenant, tenantID)
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{err: sql.ErrNoRows, code: codes.NotFound},
+		{err: task.ErrInvalidStatus, code: codes.InvalidArgument},
+		{err: task.ErrForbidden, code: codes.PermissionDenied},
+		{err: task.ErrQuotaExceeded, code: codes.ResourceExhausted},
+		{err: assert.AnError, code: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, status.Code(toStatus(tt.err)), tt.err.Error())
+	}
+}
@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"TaskService/internal/tenant"
+	"TaskService/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tenantFromMetadata кладет в контекст тенант вызова: метаданные x-tenant-id проверяются
+// по тем же правилам, что и заголовок X-Tenant-ID в HTTP API (tenant.Resolve).
+func tenantFromMetadata(ctx context.Context) (context.Context, error) {
+	var requested string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(strings.ToLower(tenant.Header)); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	id, err := tenant.Resolve(ctx, requested)
+	switch {
+	case errors.Is(err, tenant.ErrInvalid):
+		return nil, status.Error(codes.InvalidArgument, "invalid tenant")
+	case err != nil:
+		return nil, status.Error(codes.PermissionDenied, "forbidden")
+	}
+
+	ctx = tenant.WithID(ctx, id)
+
+	log := logger.FromContext(ctx).With().Str("tenant_id", id).Logger()
+
+	return logger.WrapToContext(ctx, log), nil
+}
+
+func unaryTenantInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := tenantFromMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func streamTenantInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := tenantFromMetadata(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
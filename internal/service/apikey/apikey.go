@@ -0,0 +1,239 @@
+package apikey
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/internal/dto"
+	"TaskService/internal/model"
+	"TaskService/internal/storage"
+	"TaskService/internal/tenant"
+	"TaskService/pkg/logger"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidScope  = errors.New("invalid scope")
+	ErrInvalidName   = errors.New("invalid name")
+	ErrInvalidTenant = errors.New("invalid tenant")
+	ErrNotFound      = errors.New("api key not found")
+	ErrForbidden     = errors.New("forbidden")
+)
+
+const (
+	keyPrefix = "tsk"
+	// lastUsedResolution ограничивает частоту записи last_used_at: не чаще раза в минуту на ключ.
+	lastUsedResolution = time.Minute
+)
+
+var knownScopes = map[string]struct{}{
+	auth.ScopeTasksRead:  {},
+	auth.ScopeTasksWrite: {},
+	auth.ScopeAdmin:      {},
+}
+
+type Service interface {
+	Create(ctx context.Context, req dto.CreateAPIKeyRequest) (dto.CreateAPIKeyResponse, error)
+	List(ctx context.Context) (dto.GetAPIKeyListResponse, error)
+	Revoke(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, key string) (auth.Principal, error)
+}
+
+type service struct {
+	st  storage.Storage
+	now func() time.Time
+}
+
+func New(st storage.Storage) Service {
+	result := &service{
+		st:  st,
+		now: time.Now,
+	}
+
+	return result
+}
+
+func (s *service) Create(ctx context.Context, req dto.CreateAPIKeyRequest) (dto.CreateAPIKeyResponse, error) {
+	var resp dto.CreateAPIKeyResponse
+
+	log := logger.FromContext(ctx)
+
+	if strings.TrimSpace(req.Name) == "" {
+		return resp, ErrInvalidName
+	}
+
+	for _, scope := range req.Scopes {
+		if _, ok := knownScopes[scope]; !ok {
+			return resp, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	if req.TenantID != "" && !tenant.Valid(req.TenantID) {
+		return resp, ErrInvalidTenant
+	}
+
+	owner, err := owner(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	// администратор тенанта создает ключи только своего тенанта, ключ без тенанта
+	// (с выбором тенанта заголовком) может выдать лишь администратор без тенанта
+	if owner != "" {
+		if req.TenantID != "" && req.TenantID != owner {
+			return resp, ErrForbidden
+		}
+		req.TenantID = owner
+	}
+
+	plain, prefix, err := generateKey()
+	if err != nil {
+		log.Error().Err(err).Msg("generate api key failed")
+		return resp, err
+	}
+
+	key, err := s.st.APIKeys().Create(ctx, model.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hashKey(plain),
+		Scopes:    req.Scopes,
+		TenantID:  req.TenantID,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		log.Info().Err(err).Msg("create api key failed")
+		return resp, err
+	}
+
+	resp = dto.CreateAPIKeyResponse{
+		GetAPIKeyResponse: dto.NewGetAPIKeyResponse(key),
+		Key:               plain,
+	}
+
+	return resp, nil
+}
+
+func (s *service) List(ctx context.Context) (dto.GetAPIKeyListResponse, error) {
+	resp := dto.GetAPIKeyListResponse{
+		APIKeys: make([]dto.GetAPIKeyResponse, 0),
+	}
+
+	log := logger.FromContext(ctx)
+
+	owner, err := owner(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	keys, err := s.st.APIKeys().List(ctx, owner)
+	if err != nil {
+		log.Info().Err(err).Msg("list api keys failed")
+		return resp, err
+	}
+
+	for _, key := range keys {
+		resp.APIKeys = append(resp.APIKeys, dto.NewGetAPIKeyResponse(key))
+	}
+
+	return resp, nil
+}
+
+func (s *service) Revoke(ctx context.Context, id int) error {
+	log := logger.FromContext(ctx)
+
+	owner, err := owner(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.st.APIKeys().Revoke(ctx, id, owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		log.Info().Err(err).Msg("revoke api key failed")
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) Authenticate(ctx context.Context, plain string) (auth.Principal, error) {
+	log := logger.FromContext(ctx)
+
+	key, err := s.st.APIKeys().GetByHash(ctx, hashKey(plain))
+	if errors.Is(err, sql.ErrNoRows) {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		log.Info().Err(err).Msg("get api key failed")
+		return auth.Principal{}, err
+	}
+
+	now := s.now()
+
+	if key.RevokedAt != nil {
+		return auth.Principal{}, fmt.Errorf("%w: api key revoked", auth.ErrInvalidCredentials)
+	}
+
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		return auth.Principal{}, fmt.Errorf("%w: api key expired", auth.ErrInvalidCredentials)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.st.APIKeys().Touch(ctx, key.ID, now); err != nil {
+			log.Info().Err(err).Msg("touch api key failed")
+		}
+	}
+
+	result := auth.Principal{
+		Subject: fmt.Sprintf("apikey:%d", key.ID),
+		Scopes:  key.Scopes,
+		Tenant:  key.TenantID,
+	}
+
+	return result, nil
+}
+
+// owner возвращает тенант, ключами которого управляет вызывающий. Пустая строка — субъект
+// без тенанта или доверенный вызов, им доступны ключи всех тенантов.
+func owner(ctx context.Context) (string, error) {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.Tenant, nil
+	}
+
+	if auth.Trusted(ctx) {
+		return "", nil
+	}
+
+	return "", ErrForbidden
+}
+
+// generateKey возвращает ключ вида tsk_<prefix>_<secret> и его публичный префикс.
+func generateKey() (string, string, error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix := keyPrefix + "_" + hex.EncodeToString(id)
+
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+func hashKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
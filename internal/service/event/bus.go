@@ -0,0 +1,147 @@
+package event
+
+import (
+	"TaskService/internal/model"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHistorySize = 1024
+	defaultBufferSize  = 64
+)
+
+type Bus interface {
+	// Publish передает подписчикам событие с ID id. ID выдает источник события, поэтому у всех
+	// реплик он одинаковый.
+	Publish(id string, eventType Type, task model.Task) Event
+	// Subscribe подписывает на события, подходящие под фильтр. Если lastID не пуст,
+	// сначала воспроизводятся сохраненные события после него. Если lastID в истории нет
+	// (история вытеснена, сервис перезапущен или клиент переподключился к другой реплике),
+	// первым приходит событие TypeResync.
+	Subscribe(filter Filter, lastID string) Subscription
+}
+
+type Subscription interface {
+	// Events закрывается при вызове Close или если подписчик не успевает вычитывать события.
+	Events() <-chan Event
+	Close()
+}
+
+type bus struct {
+	mu      sync.Mutex
+	history []Event
+	subs    map[*subscription]struct{}
+}
+
+func New() Bus {
+	result := &bus{
+		history: make([]Event, 0, defaultHistorySize),
+		subs:    make(map[*subscription]struct{}),
+	}
+
+	return result
+}
+
+func (b *bus) Publish(id string, eventType Type, task model.Task) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e := Event{
+		ID:   id,
+		Type: eventType,
+		Time: time.Now().UTC(),
+		Task: task,
+	}
+
+	if len(b.history) == defaultHistorySize {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:defaultHistorySize-1]
+	}
+	b.history = append(b.history, e)
+
+	for sub := range b.subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+
+		select {
+		case sub.ch <- e:
+		default:
+			b.remove(sub)
+		}
+	}
+
+	return e
+}
+
+func (b *bus) Subscribe(filter Filter, lastID string) Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []Event
+	if lastID != "" {
+		backlog = b.replay(filter, lastID)
+	}
+
+	sub := &subscription{
+		bus:    b,
+		filter: filter,
+		ch:     make(chan Event, defaultBufferSize+len(backlog)),
+	}
+
+	for _, e := range backlog {
+		sub.ch <- e
+	}
+
+	b.subs[sub] = struct{}{}
+
+	return sub
+}
+
+// replay возвращает сохраненные события после lastID или событие TypeResync, если lastID
+// в истории нет.
+func (b *bus) replay(filter Filter, lastID string) []Event {
+	for i, e := range b.history {
+		if e.ID != lastID {
+			continue
+		}
+
+		var result []Event
+		for _, e := range b.history[i+1:] {
+			if filter.Match(e) {
+				result = append(result, e)
+			}
+		}
+
+		return result
+	}
+
+	return []Event{{Type: TypeResync, Time: time.Now().UTC()}}
+}
+
+func (b *bus) remove(sub *subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+type subscription struct {
+	bus    *bus
+	filter Filter
+	ch     chan Event
+}
+
+func (s *subscription) Events() <-chan Event {
+	return s.ch
+}
+
+func (s *subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
@@ -0,0 +1,83 @@
+package event
+
+import (
+	"TaskService/internal/model"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	b := New()
+
+	sub := b.Subscribe(Filter{Status: "done"}, "")
+	defer sub.Close()
+
+	b.Publish("a:task.created", TypeCreated, model.Task{ID: 1, Status: "created"})
+	published := b.Publish("b:task.status_changed", TypeStatusChanged, model.Task{ID: 1, Status: "done"})
+
+	e := <-sub.Events()
+	assert.Equal(t, published, e)
+	assert.Len(t, sub.Events(), 0)
+}
+
+func TestBus_SubscribeReplaysAfterLastID(t *testing.T) {
+	b := New()
+
+	first := b.Publish("a:task.created", TypeCreated, model.Task{ID: 1})
+	second := b.Publish("b:task.created", TypeCreated, model.Task{ID: 2})
+	third := b.Publish("c:task.updated", TypeUpdated, model.Task{ID: 1})
+
+	sub := b.Subscribe(Filter{TaskIDs: []int{1}}, first.ID)
+	defer sub.Close()
+
+	e := <-sub.Events()
+	assert.Equal(t, third, e)
+	assert.NotEqual(t, second.Task.ID, e.Task.ID)
+	assert.Len(t, sub.Events(), 0)
+}
+
+func TestBus_SubscribeResyncsUnknownLastID(t *testing.T) {
+	b := New()
+
+	b.Publish("a:task.created", TypeCreated, model.Task{ID: 1})
+
+	sub := b.Subscribe(Filter{}, "unknown")
+	defer sub.Close()
+
+	e := <-sub.Events()
+	assert.Equal(t, TypeResync, e.Type)
+	assert.Empty(t, e.ID)
+	assert.Len(t, sub.Events(), 0)
+
+	published := b.Publish("b:task.updated", TypeUpdated, model.Task{ID: 1})
+	assert.Equal(t, published, <-sub.Events())
+}
+
+func TestBus_DropsSlowSubscriber(t *testing.T) {
+	b := New()
+
+	sub := b.Subscribe(Filter{}, "")
+
+	for i := 0; i <= defaultBufferSize; i++ {
+		b.Publish(strconv.Itoa(i), TypeCreated, model.Task{ID: i})
+	}
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+
+	assert.Equal(t, defaultBufferSize, received)
+
+	sub.Close()
+}
+
+func TestFilter_Subject(t *testing.T) {
+	filter := Filter{Subject: "user-1"}
+
+	assert.True(t, filter.Match(Event{Task: model.Task{CreatedBy: "user-1"}}))
+	assert.True(t, filter.Match(Event{Task: model.Task{Assignee: "user-1"}}))
+	assert.False(t, filter.Match(Event{Task: model.Task{CreatedBy: "user-2", Assignee: "user-3"}}))
+}
@@ -0,0 +1,59 @@
+package event
+
+import (
+	"TaskService/internal/model"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeCreated       Type = "task.created"
+	TypeUpdated       Type = "task.updated"
+	TypeStatusChanged Type = "task.status_changed"
+	// TypeResync сообщает, что пропущенные события восстановить нельзя и состояние задач
+	// нужно перечитать. У события нет ID и задачи.
+	TypeResync Type = "resync"
+)
+
+type Event struct {
+	ID   string
+	Type Type
+	Time time.Time
+	Task model.Task
+}
+
+// Filter отбирает события по ID задачи и/или статусу. Пустой фильтр пропускает все события.
+type Filter struct {
+	TaskIDs []int
+	Status  string
+	// Subject оставляет только задачи, созданные субъектом или назначенные на него.
+	Subject string
+	Tenant  string
+}
+
+func (f Filter) Match(e Event) bool {
+	if f.Status != "" && f.Status != e.Task.Status {
+		return false
+	}
+
+	if f.Tenant != "" && f.Tenant != e.Task.TenantID {
+		return false
+	}
+
+	if f.Subject != "" && f.Subject != e.Task.CreatedBy && f.Subject != e.Task.Assignee {
+		return false
+	}
+
+	if len(f.TaskIDs) == 0 {
+		return true
+	}
+
+	for _, id := range f.TaskIDs {
+		if id == e.Task.ID {
+			return true
+		}
+	}
+
+	return false
+}
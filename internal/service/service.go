@@ -1,6 +1,8 @@
 package service
 
 import (
+	"TaskService/internal/service/apikey"
+	"TaskService/internal/service/event"
 	"TaskService/internal/service/task"
 	"TaskService/internal/storage"
 	"TaskService/pkg/kafka"
@@ -8,16 +10,19 @@ import (
 
 type Service interface {
 	Task() task.Service
+	APIKey() apikey.Service
 }
 
 type service struct {
-	task task.Service
+	task   task.Service
+	apiKey apikey.Service
 }
 
-func New(st storage.Storage, kc kafka.Kafka) Service {
+func New(st storage.Storage, kc kafka.Kafka, taskCfg task.Config) Service {
 
 	result := &service{
-		task: task.New(st, kc),
+		task:   task.New(st, kc, event.New(), taskCfg),
+		apiKey: apikey.New(st),
 	}
 
 	return result
@@ -26,3 +31,7 @@ func New(st storage.Storage, kc kafka.Kafka) Service {
 func (s *service) Task() task.Service {
 	return s.task
 }
+
+func (s *service) APIKey() apikey.Service {
+	return s.apiKey
+}
@@ -1,20 +1,31 @@
 package service_test
 
 import (
+	"TaskService/internal/auth"
 	"TaskService/internal/dto"
 	"TaskService/internal/model"
+	"TaskService/internal/service/apikey"
+	"TaskService/internal/service/event"
 	"TaskService/internal/service/task"
 	"TaskService/internal/storage/postgres"
+	"TaskService/internal/tenant"
+	"TaskService/pkg/events"
+	"TaskService/pkg/kafka"
 	"TaskService/pkg/logger"
+	"TaskService/pkg/retry"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock Storage
@@ -27,6 +38,26 @@ func (m *MockStorage) DB() postgres.Storage {
 	return args.Get(0).(postgres.Storage)
 }
 
+func (m *MockStorage) APIKeys() postgres.APIKeyStorage {
+	args := m.Called()
+	return args.Get(0).(postgres.APIKeyStorage)
+}
+
+func (m *MockStorage) RateLimits() postgres.RateLimitStorage {
+	args := m.Called()
+	return args.Get(0).(postgres.RateLimitStorage)
+}
+
+func (m *MockStorage) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockStorage) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 // Mock PostgresStorage
 type MockPostgresStorage struct {
 	mock.Mock
@@ -37,8 +68,13 @@ func (m *MockPostgresStorage) Get(ctx context.Context, id int) (model.Task, erro
 	return args.Get(0).(model.Task), args.Error(1)
 }
 
-func (m *MockPostgresStorage) GetList(ctx context.Context) ([]model.Task, error) {
-	args := m.Called(ctx)
+func (m *MockPostgresStorage) GetForUpdate(ctx context.Context, tx postgres.Tx, id int) (model.Task, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
+func (m *MockPostgresStorage) GetList(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]model.Task), args.Error(1)
 }
 
@@ -52,6 +88,11 @@ func (m *MockPostgresStorage) Create(ctx context.Context, tx postgres.Tx, task m
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockPostgresStorage) Count(ctx context.Context, tx postgres.Tx) (int, error) {
+	args := m.Called(ctx, tx)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockPostgresStorage) BeginTx(ctx context.Context) (postgres.Tx, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(postgres.Tx), args.Error(1)
@@ -62,16 +103,31 @@ type MockKafka struct {
 	mock.Mock
 }
 
-func (m *MockKafka) SendMessage(message []byte) error {
-	args := m.Called(message)
+func (m *MockKafka) SendMessage(ctx context.Context, message kafka.Message) error {
+	args := m.Called(ctx, message)
 	return args.Error(0)
 }
 
-func (m *MockKafka) ConsumeMessages(handler func(message *sarama.ConsumerMessage)) error {
-	args := m.Called(handler)
+func (m *MockKafka) ConsumeMessages(ctx context.Context, handler func(message *sarama.ConsumerMessage)) error {
+	args := m.Called(ctx, handler)
+	return args.Error(0)
+}
+
+func (m *MockKafka) ConsumeAll(ctx context.Context, handler func(message *sarama.ConsumerMessage)) error {
+	args := m.Called(ctx, handler)
+	return args.Error(0)
+}
+
+func (m *MockKafka) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
 	return args.Error(0)
 }
 
+func (m *MockKafka) ConsumerAlive() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
 func (m *MockKafka) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -141,7 +197,7 @@ func setupTest(t *testing.T) (*MockStorage, *MockPostgresStorage, *MockKafka, *M
 func TestTaskService_Get(t *testing.T) {
 	mockStorage, mockPostgres, mockKafka, _ := setupTest(t)
 
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	taskID := 1
 	expectedTask := model.Task{
 		ID:          1,
@@ -153,7 +209,7 @@ func TestTaskService_Get(t *testing.T) {
 	mockStorage.On("DB").Return(mockPostgres)
 	mockPostgres.On("Get", ctx, taskID).Return(expectedTask, nil)
 
-	service := task.New(mockStorage, mockKafka)
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
 	result, err := service.Get(ctx, taskID)
 
 	assert.NoError(t, err)
@@ -169,17 +225,17 @@ func TestTaskService_Get(t *testing.T) {
 func TestTaskService_GetList(t *testing.T) {
 	mockStorage, mockPostgres, mockKafka, _ := setupTest(t)
 
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	expectedTasks := []model.Task{
 		{ID: 1, Title: "Task 1", Description: "Desc 1", Status: "created"},
 		{ID: 2, Title: "Task 2", Description: "Desc 2", Status: "done"},
 	}
 
 	mockStorage.On("DB").Return(mockPostgres)
-	mockPostgres.On("GetList", ctx).Return(expectedTasks, nil)
+	mockPostgres.On("GetList", ctx, model.TaskFilter{Status: "done", Limit: 10}).Return(expectedTasks, nil)
 
-	service := task.New(mockStorage, mockKafka)
-	result, err := service.GetList(ctx)
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
+	result, err := service.GetList(ctx, dto.GetTaskListRequest{Status: "done", Limit: 10})
 
 	assert.NoError(t, err)
 	assert.Len(t, result.Tasks, 2)
@@ -193,7 +249,7 @@ func TestTaskService_GetList(t *testing.T) {
 func TestTaskService_Create(t *testing.T) {
 	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
 
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	createReq := dto.CreateTaskRequest{
 		Title:       "New Task",
 		Description: "New Description",
@@ -205,12 +261,13 @@ func TestTaskService_Create(t *testing.T) {
 	mockPostgres.On("Create", ctx, mockTx, model.Task{
 		Title:       createReq.Title,
 		Description: createReq.Description,
+		TenantID:    tenant.Default,
 	}).Return(expectedID, nil)
-	mockKafka.On("SendMessage", mock.Anything).Return(nil)
+	mockKafka.On("SendMessage", mock.Anything, mock.Anything).Return(nil)
 	mockTx.On("Commit").Return(nil)
 	mockTx.On("Rollback").Return(nil)
 
-	service := task.New(mockStorage, mockKafka)
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
 	err := service.Create(ctx, createReq)
 
 	assert.NoError(t, err)
@@ -223,7 +280,7 @@ func TestTaskService_Create(t *testing.T) {
 func TestTaskService_Update(t *testing.T) {
 	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
 
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	updateReq := dto.UpdateTaskRequest{
 		ID:          1,
 		Title:       "Updated Task",
@@ -233,6 +290,7 @@ func TestTaskService_Update(t *testing.T) {
 
 	// ДОБАВЛЕНО: моки для транзакции в Update
 	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("GetForUpdate", ctx, mockTx, updateReq.ID).Return(model.Task{ID: updateReq.ID, Status: "created"}, nil)
 	mockPostgres.On("BeginTx", ctx).Return(mockTx, nil)
 	mockPostgres.On("Update", ctx, mockTx, model.Task{
 		ID:          updateReq.ID,
@@ -240,22 +298,24 @@ func TestTaskService_Update(t *testing.T) {
 		Description: updateReq.Description,
 		Status:      updateReq.Status,
 	}).Return(nil)
+	mockKafka.On("SendMessage", mock.Anything, mock.Anything).Return(nil)
 	mockTx.On("Commit").Return(nil)
 	mockTx.On("Rollback").Return(nil)
 
-	service := task.New(mockStorage, mockKafka)
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
 	err := service.Update(ctx, updateReq)
 
 	assert.NoError(t, err)
 	mockStorage.AssertExpectations(t)
 	mockPostgres.AssertExpectations(t)
+	mockKafka.AssertNumberOfCalls(t, "SendMessage", 2)
 	mockTx.AssertExpectations(t) // ДОБАВЛЕНО: проверка мока транзакции
 }
 
 func TestTaskService_Update_InvalidStatus(t *testing.T) {
 	mockStorage, _, mockKafka, _ := setupTest(t)
 
-	ctx := context.Background()
+	ctx := auth.WithTrusted(context.Background())
 	updateReq := dto.UpdateTaskRequest{
 		ID:          1,
 		Title:       "Updated Task",
@@ -263,9 +323,444 @@ func TestTaskService_Update_InvalidStatus(t *testing.T) {
 		Status:      "invalid_status",
 	}
 
-	service := task.New(mockStorage, mockKafka)
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
 	err := service.Update(ctx, updateReq)
 
 	assert.Error(t, err)
 	assert.Equal(t, "invalid status", err.Error())
 }
+
+func TestTaskService_Update_PublishesEvents(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
+
+	ctx := auth.WithTrusted(context.Background())
+	updateReq := dto.UpdateTaskRequest{
+		ID:     1,
+		Title:  "Updated Task",
+		Status: "done",
+	}
+
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("GetForUpdate", ctx, mockTx, updateReq.ID).Return(model.Task{ID: updateReq.ID, Status: "created"}, nil)
+	mockPostgres.On("BeginTx", ctx).Return(mockTx, nil)
+	mockPostgres.On("Update", ctx, mockTx, mock.Anything).Return(nil)
+	mockKafka.On("SendMessage", mock.Anything, mock.Anything).Return(nil)
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	bus := event.New()
+	sub := bus.Subscribe(event.Filter{TaskIDs: []int{updateReq.ID}}, "")
+	defer sub.Close()
+
+	service := task.New(mockStorage, mockKafka, bus, task.Config{})
+	err := service.Update(ctx, updateReq)
+	assert.NoError(t, err)
+
+	updated := <-sub.Events()
+	assert.Equal(t, event.TypeUpdated, updated.Type)
+
+	changed := <-sub.Events()
+	assert.Equal(t, event.TypeStatusChanged, changed.Type)
+	assert.Equal(t, "done", changed.Task.Status)
+}
+
+func TestTaskService_Update_KafkaEvents(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
+
+	ctx := auth.WithTrusted(context.Background())
+	updateReq := dto.UpdateTaskRequest{ID: 7, Title: "Task", Status: "done"}
+
+	var sent []events.Event
+
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("GetForUpdate", ctx, mockTx, updateReq.ID).Return(model.Task{ID: updateReq.ID, Status: "created", TenantID: "sales"}, nil)
+	mockPostgres.On("BeginTx", ctx).Return(mockTx, nil)
+	mockPostgres.On("Update", ctx, mockTx, mock.Anything).Return(nil)
+	mockKafka.On("SendMessage", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		msg := args.Get(1).(kafka.Message)
+
+		e, err := events.Parse(msg.Value)
+		require.NoError(t, err)
+
+		assert.Equal(t, "7", string(msg.Key))
+		assert.Equal(t, events.ContentType, msg.Headers[events.HeaderContentType])
+		assert.Equal(t, e.Type, msg.Headers[events.HeaderType])
+		assert.Equal(t, "sales", msg.Headers[tenant.KafkaHeader])
+
+		sent = append(sent, e)
+	}).Return(nil)
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	bus := event.New()
+	sub := bus.Subscribe(event.Filter{}, "")
+	defer sub.Close()
+
+	service := task.New(mockStorage, mockKafka, bus, task.Config{})
+	require.NoError(t, service.Update(ctx, updateReq))
+
+	require.Len(t, sent, 2)
+	assert.Equal(t, events.TypeTaskUpdated, sent[0].Type)
+	assert.Equal(t, events.TypeTaskCompleted, sent[1].Type)
+	assert.Equal(t, "7", sent[1].Subject)
+	assert.Equal(t, events.Source, sent[1].Source)
+	assert.Equal(t, "sales", sent[1].TenantID)
+	assert.Equal(t, "done", sent[1].Data.Status)
+	assert.NotEqual(t, sent[0].ID, sent[1].ID)
+
+	// ID событий подписчиков выводятся из ID события Kafka, как и на репликах, читающих топик
+	assert.Equal(t, sent[0].ID+":task.updated", (<-sub.Events()).ID)
+	assert.Equal(t, sent[0].ID+":task.status_changed", (<-sub.Events()).ID)
+}
+
+func TestTaskService_ConsumeEvents(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
+
+	ctx := auth.WithTrusted(context.Background())
+	updateReq := dto.UpdateTaskRequest{ID: 3, Title: "Task", Status: "done"}
+
+	value, err := json.Marshal(events.New(events.TypeTaskCreated, events.Task{ID: 9, Status: "created"}))
+	require.NoError(t, err)
+
+	mockKafka.On("ConsumeAll", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handler := args.Get(1).(func(message *sarama.ConsumerMessage))
+		handler(&sarama.ConsumerMessage{Value: value})
+	}).Return(nil)
+
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("GetForUpdate", ctx, mockTx, updateReq.ID).Return(model.Task{ID: updateReq.ID, Status: "created"}, nil)
+	mockPostgres.On("BeginTx", ctx).Return(mockTx, nil)
+	mockPostgres.On("Update", ctx, mockTx, mock.Anything).Return(nil)
+	mockKafka.On("SendMessage", mock.Anything, mock.Anything).Return(nil)
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	bus := event.New()
+	sub := bus.Subscribe(event.Filter{}, "")
+	defer sub.Close()
+
+	service := task.New(mockStorage, mockKafka, bus, task.Config{})
+	require.NoError(t, service.ConsumeEvents(context.Background()))
+
+	created := <-sub.Events()
+	assert.Equal(t, event.TypeCreated, created.Type)
+	assert.Equal(t, 9, created.Task.ID)
+
+	// изменения этого процесса придут из Kafka, напрямую в bus они больше не попадают
+	require.NoError(t, service.Update(ctx, updateReq))
+
+	select {
+	case e := <-sub.Events():
+		t.Fatalf("unexpected local event %s", e.Type)
+	default:
+	}
+}
+
+func TestTaskService_ProcessTasks_RetriesUncommittedTask(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
+
+	value, err := json.Marshal(events.New(events.TypeTaskCreated, events.Task{ID: 5}))
+	require.NoError(t, err)
+
+	mockKafka.On("ConsumeMessages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handler := args.Get(1).(func(message *sarama.ConsumerMessage))
+		handler(&sarama.ConsumerMessage{Value: value})
+	}).Return(nil)
+
+	// транзакция создания еще не зафиксирована: первое чтение не находит задачу
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("Get", mock.Anything, 5).Return(model.Task{}, sql.ErrNoRows).Once()
+	mockPostgres.On("Get", mock.Anything, 5).Return(model.Task{ID: 5, Status: "created"}, nil).Once()
+	mockPostgres.On("BeginTx", mock.Anything).Return(mockTx, nil)
+	mockPostgres.On("GetForUpdate", mock.Anything, mockTx, 5).Return(model.Task{ID: 5, Status: "created"}, nil)
+	mockPostgres.On("Update", mock.Anything, mockTx, mock.MatchedBy(func(task model.Task) bool {
+		return task.ID == 5 && task.Status == "done"
+	})).Return(nil)
+	mockKafka.On("SendMessage", mock.Anything, mock.Anything).Return(nil)
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	cfg := task.Config{Retry: retry.Config{InitialInterval: time.Millisecond, MaxAttempts: 3}}
+	service := task.New(mockStorage, mockKafka, event.New(), cfg)
+	require.NoError(t, service.ProcessTasks(context.Background()))
+
+	mockPostgres.AssertExpectations(t)
+}
+
+func TestTaskService_RBAC_Get(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, _ := setupTest(t)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Scopes: []string{auth.ScopeTasksRead}})
+
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("Get", ctx, 1).Return(model.Task{ID: 1, CreatedBy: "bob", Assignee: "alice"}, nil)
+	mockPostgres.On("Get", ctx, 2).Return(model.Task{ID: 2, CreatedBy: "bob"}, nil)
+
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
+
+	result, err := service.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", result.Assignee)
+
+	_, err = service.Get(ctx, 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestTaskService_RBAC_GetList(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, _ := setupTest(t)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Scopes: []string{auth.ScopeTasksRead}})
+	filter := model.TaskFilter{Assignee: "alice", VisibleTo: "alice"}
+
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("GetList", ctx, filter).Return([]model.Task{{ID: 1, Assignee: "alice"}}, nil)
+
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
+
+	result, err := service.GetList(ctx, dto.GetTaskListRequest{Assignee: "me"})
+	assert.NoError(t, err)
+	assert.Len(t, result.Tasks, 1)
+
+	_, err = service.GetList(auth.WithTrusted(context.Background()), dto.GetTaskListRequest{Assignee: "me"})
+	assert.ErrorIs(t, err, task.ErrInvalidAssignee)
+
+	mockPostgres.AssertExpectations(t)
+}
+
+func TestTaskService_RBAC_Write(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
+
+	viewer := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Scopes: []string{auth.ScopeTasksRead}})
+	editor := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Scopes: []string{auth.ScopeTasksWrite}})
+
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
+
+	err := service.Create(viewer, dto.CreateTaskRequest{Title: "Task"})
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	err = service.Update(viewer, dto.UpdateTaskRequest{ID: 1, Title: "Task", Status: "done"})
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("BeginTx", editor).Return(mockTx, nil)
+	mockPostgres.On("Create", editor, mockTx, model.Task{Title: "Task", CreatedBy: "alice", Assignee: "alice", TenantID: tenant.Default}).Return(1, nil)
+	mockKafka.On("SendMessage", mock.Anything, mock.Anything).Return(nil)
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	err = service.Create(editor, dto.CreateTaskRequest{Title: "Task", Assignee: "me"})
+	assert.NoError(t, err)
+
+	mockPostgres.On("GetForUpdate", editor, mockTx, 2).Return(model.Task{ID: 2, CreatedBy: "bob"}, nil)
+
+	err = service.Update(editor, dto.UpdateTaskRequest{ID: 2, Title: "Task", Status: "done"})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	mockPostgres.AssertExpectations(t)
+}
+
+func TestTaskService_NoPrincipal(t *testing.T) {
+	mockStorage, _, mockKafka, _ := setupTest(t)
+
+	// вызов без Principal и без пометки доверенного не доходит до хранилища
+	ctx := context.Background()
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
+
+	_, err := service.Get(ctx, 1)
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	_, err = service.GetList(ctx, dto.GetTaskListRequest{})
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	err = service.Create(ctx, dto.CreateTaskRequest{Title: "Task"})
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	err = service.Update(ctx, dto.UpdateTaskRequest{ID: 1, Title: "Task", Status: "done"})
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	_, err = service.Subscribe(ctx, event.Filter{}, "")
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	mockStorage.AssertNotCalled(t, "DB")
+}
+
+func TestTaskService_NoTaskScope(t *testing.T) {
+	mockStorage, _, mockKafka, _ := setupTest(t)
+
+	// ключ без scopes tasks:* и admin не получает даже чтения
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "apikey:1", Scopes: []string{}})
+	service := task.New(mockStorage, mockKafka, event.New(), task.Config{})
+
+	_, err := service.Get(ctx, 1)
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	_, err = service.GetList(ctx, dto.GetTaskListRequest{})
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	_, err = service.Subscribe(ctx, event.Filter{}, "")
+	assert.ErrorIs(t, err, task.ErrForbidden)
+
+	mockStorage.AssertNotCalled(t, "DB")
+}
+
+func TestTaskService_Create_Quota(t *testing.T) {
+	mockStorage, mockPostgres, mockKafka, mockTx := setupTest(t)
+
+	ctx := tenant.WithID(auth.WithTrusted(context.Background()), "sales")
+	cfg := task.Config{MaxTasks: 100, Quotas: map[string]int{"sales": 2}}
+
+	mockStorage.On("DB").Return(mockPostgres)
+	mockPostgres.On("BeginTx", ctx).Return(mockTx, nil)
+	mockPostgres.On("Count", ctx, mockTx).Return(1, nil).Once()
+	mockPostgres.On("Count", ctx, mockTx).Return(2, nil).Once()
+	mockPostgres.On("Create", ctx, mockTx, mock.Anything).Return(1, nil).Once()
+	mockKafka.On("SendMessage", mock.Anything, mock.MatchedBy(func(msg kafka.Message) bool {
+		return msg.Headers[tenant.KafkaHeader] == "sales"
+	})).Return(nil).Once()
+	mockTx.On("Commit").Return(nil)
+	mockTx.On("Rollback").Return(nil)
+
+	service := task.New(mockStorage, mockKafka, event.New(), cfg)
+
+	err := service.Create(ctx, dto.CreateTaskRequest{Title: "Task"})
+	assert.NoError(t, err)
+
+	err = service.Create(ctx, dto.CreateTaskRequest{Title: "Task"})
+	assert.ErrorIs(t, err, task.ErrQuotaExceeded)
+
+	mockPostgres.AssertExpectations(t)
+	mockKafka.AssertExpectations(t)
+}
+
+// Mock APIKeyStorage
+type MockAPIKeyStorage struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyStorage) Create(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyStorage) List(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).([]model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyStorage) GetByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyStorage) Revoke(ctx context.Context, id int, tenantID string) error {
+	args := m.Called(ctx, id, tenantID)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyStorage) Touch(ctx context.Context, id int, usedAt time.Time) error {
+	args := m.Called(ctx, id, usedAt)
+	return args.Error(0)
+}
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	mockStorage := &MockStorage{}
+	mockKeys := &MockAPIKeyStorage{}
+	mockStorage.On("APIKeys").Return(mockKeys)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "root", Scopes: []string{auth.ScopeAdmin}})
+
+	var stored model.APIKey
+	mockKeys.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(model.APIKey)
+		stored.ID = 7
+	}).Return(model.APIKey{ID: 7, Name: "batch", Scopes: []string{"tasks:read"}}, nil)
+
+	service := apikey.New(mockStorage)
+
+	resp, err := service.Create(ctx, dto.CreateAPIKeyRequest{Name: "batch", Scopes: []string{"tasks:read"}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Key)
+	assert.True(t, strings.HasPrefix(resp.Key, stored.Prefix+"_"))
+	assert.NotContains(t, stored.KeyHash, resp.Key)
+
+	mockKeys.On("GetByHash", ctx, stored.KeyHash).Return(stored, nil)
+	mockKeys.On("Touch", ctx, 7, mock.Anything).Return(nil)
+
+	principal, err := service.Authenticate(ctx, resp.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "apikey:7", principal.Subject)
+	assert.True(t, principal.HasScope("tasks:read"))
+
+	mockKeys.AssertExpectations(t)
+}
+
+func TestAPIKeyService_TenantScope(t *testing.T) {
+	mockStorage := &MockStorage{}
+	mockKeys := &MockAPIKeyStorage{}
+	mockStorage.On("APIKeys").Return(mockKeys)
+
+	hrAdmin := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "hr-admin", Scopes: []string{auth.ScopeAdmin}, Tenant: "hr"})
+	root := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "root", Scopes: []string{auth.ScopeAdmin}})
+
+	mockKeys.On("Create", hrAdmin, mock.MatchedBy(func(key model.APIKey) bool {
+		return key.TenantID == "hr"
+	})).Return(model.APIKey{ID: 1, TenantID: "hr"}, nil).Once()
+	mockKeys.On("List", hrAdmin, "hr").Return([]model.APIKey{{ID: 1, TenantID: "hr"}}, nil).Once()
+	mockKeys.On("List", root, "").Return([]model.APIKey{{ID: 1, TenantID: "hr"}, {ID: 2}}, nil).Once()
+	mockKeys.On("Revoke", hrAdmin, 2, "hr").Return(sql.ErrNoRows).Once()
+
+	service := apikey.New(mockStorage)
+
+	// ключ администратора тенанта всегда привязан к его тенанту
+	resp, err := service.Create(hrAdmin, dto.CreateAPIKeyRequest{Name: "batch", Scopes: []string{auth.ScopeAdmin}})
+	require.NoError(t, err)
+	assert.Equal(t, "hr", resp.TenantID)
+
+	_, err = service.Create(hrAdmin, dto.CreateAPIKeyRequest{Name: "batch", TenantID: "sales"})
+	assert.ErrorIs(t, err, apikey.ErrForbidden)
+
+	_, err = service.Create(context.Background(), dto.CreateAPIKeyRequest{Name: "batch"})
+	assert.ErrorIs(t, err, apikey.ErrForbidden)
+
+	list, err := service.List(hrAdmin)
+	require.NoError(t, err)
+	assert.Len(t, list.APIKeys, 1)
+
+	list, err = service.List(root)
+	require.NoError(t, err)
+	assert.Len(t, list.APIKeys, 2)
+
+	err = service.Revoke(hrAdmin, 2)
+	assert.ErrorIs(t, err, apikey.ErrNotFound)
+
+	mockKeys.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Create_InvalidScope(t *testing.T) {
+	service := apikey.New(&MockStorage{})
+
+	_, err := service.Create(context.Background(), dto.CreateAPIKeyRequest{Name: "batch", Scopes: []string{"root"}})
+	assert.ErrorIs(t, err, apikey.ErrInvalidScope)
+}
+
+func TestAPIKeyService_Authenticate_Rejected(t *testing.T) {
+	mockStorage := &MockStorage{}
+	mockKeys := &MockAPIKeyStorage{}
+	mockStorage.On("APIKeys").Return(mockKeys)
+
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+
+	mockKeys.On("GetByHash", ctx, mock.Anything).Return(model.APIKey{}, sql.ErrNoRows).Once()
+	mockKeys.On("GetByHash", ctx, mock.Anything).Return(model.APIKey{ID: 1, ExpiresAt: &past}, nil).Once()
+	mockKeys.On("GetByHash", ctx, mock.Anything).Return(model.APIKey{ID: 2, RevokedAt: &past}, nil).Once()
+
+	service := apikey.New(mockStorage)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Authenticate(ctx, "tsk_unknown")
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	}
+
+	mockKeys.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,150 @@
+// Package servicetest содержит in-memory реализацию service.Service для тестов обработчиков,
+// gRPC и клиента.
+package servicetest
+
+import (
+	"TaskService/internal/auth"
+	"TaskService/internal/dto"
+	"TaskService/internal/model"
+	"TaskService/internal/service"
+	"TaskService/internal/service/event"
+	"TaskService/internal/service/task"
+	"TaskService/internal/tenant"
+	"context"
+	"database/sql"
+	"strconv"
+	"sync"
+)
+
+// Memory хранит задачи в памяти и передает изменения подписчикам через event.Bus.
+// Права доступа и тенанты не проверяются. APIKey не реализован.
+type Memory struct {
+	service.Service
+
+	mu    sync.Mutex
+	tasks []model.Task
+	bus   event.Bus
+	seq   int
+	// subject и tenant субъект и тенант последнего вызова Get
+	subject string
+	tenant  string
+}
+
+func NewMemory(tasks ...model.Task) *Memory {
+	return &Memory{
+		tasks: tasks,
+		bus:   event.New(),
+	}
+}
+
+func (s *Memory) Task() task.Service {
+	return s
+}
+
+// Publish передает подписчикам событие с ID "1", "2", ... по порядку.
+func (s *Memory) Publish(typ event.Type, t model.Task) event.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.publish(typ, t)
+}
+
+func (s *Memory) publish(typ event.Type, t model.Task) event.Event {
+	s.seq++
+
+	return s.bus.Publish(strconv.Itoa(s.seq), typ, t)
+}
+
+// LastGet возвращает субъект и тенант последнего вызова Get и сбрасывает их.
+func (s *Memory) LastGet() (subject, tenantID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subject, tenantID = s.subject, s.tenant
+	s.subject, s.tenant = "", ""
+
+	return subject, tenantID
+}
+
+func (s *Memory) Get(ctx context.Context, id int) (dto.GetTaskResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok := auth.FromContext(ctx); ok {
+		s.subject = p.Subject
+	}
+	s.tenant = tenant.FromContext(ctx)
+
+	for _, t := range s.tasks {
+		if t.ID == id {
+			return dto.NewGetTaskResponse(t), nil
+		}
+	}
+
+	return dto.GetTaskResponse{}, sql.ErrNoRows
+}
+
+func (s *Memory) GetList(_ context.Context, req dto.GetTaskListRequest) (dto.GetTaskListResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := dto.GetTaskListResponse{Tasks: make([]dto.GetTaskResponse, 0)}
+
+	skipped := 0
+	for _, t := range s.tasks {
+		if req.Status != "" && t.Status != req.Status {
+			continue
+		}
+
+		if skipped < req.Offset {
+			skipped++
+			continue
+		}
+
+		if req.Limit > 0 && len(resp.Tasks) == req.Limit {
+			break
+		}
+
+		resp.Tasks = append(resp.Tasks, dto.NewGetTaskResponse(t))
+	}
+
+	return resp, nil
+}
+
+func (s *Memory) Update(_ context.Context, req dto.UpdateTaskRequest) error {
+	if req.Status != "created" && req.Status != "done" {
+		return task.ErrInvalidStatus
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, t := range s.tasks {
+		if t.ID == req.ID {
+			s.tasks[i] = model.Task{ID: req.ID, Title: req.Title, Description: req.Description, Status: req.Status}
+			s.publish(event.TypeUpdated, s.tasks[i])
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+func (s *Memory) Create(_ context.Context, req dto.CreateTaskRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := model.Task{ID: len(s.tasks) + 1, Title: req.Title, Description: req.Description, Status: "created"}
+	s.tasks = append(s.tasks, t)
+	s.publish(event.TypeCreated, t)
+
+	return nil
+}
+
+func (s *Memory) Subscribe(_ context.Context, filter event.Filter, lastEventID string) (event.Subscription, error) {
+	return s.bus.Subscribe(filter, lastEventID), nil
+}
+
+func (s *Memory) ProcessTasks(context.Context) error  { return nil }
+func (s *Memory) ConsumeEvents(context.Context) error { return nil }
+func (s *Memory) SetConfig(task.Config)               {}
@@ -0,0 +1,50 @@
+package task
+
+import (
+	"time"
+
+	"TaskService/pkg/retry"
+)
+
+const (
+	defaultProcessTimeout = 30 * time.Second
+	defaultWorkers        = 10
+)
+
+type Config struct {
+	// MaxTasks лимит числа задач тенанта по умолчанию, 0 — без ограничения.
+	MaxTasks int
+	// Quotas переопределяет лимит для отдельных тенантов.
+	Quotas map[string]int
+	// ProcessTimeout ограничивает обработку одного сообщения из Kafka.
+	ProcessTimeout time.Duration
+	// Workers сколько сообщений обрабатывается одновременно, но не больше числа партиций,
+	// назначенных процессу: сообщения одной партиции обрабатываются по очереди.
+	Workers int
+	// Retry повторы чтения и обновления задачи при сбоях хранилища.
+	Retry retry.Config
+}
+
+func (c Config) workers() int {
+	if c.Workers <= 0 {
+		return defaultWorkers
+	}
+
+	return c.Workers
+}
+
+func (c Config) processTimeout() time.Duration {
+	if c.ProcessTimeout <= 0 {
+		return defaultProcessTimeout
+	}
+
+	return c.ProcessTimeout
+}
+
+func (c Config) quota(tenantID string) int {
+	if limit, ok := c.Quotas[tenantID]; ok {
+		return limit
+	}
+
+	return c.MaxTasks
+}
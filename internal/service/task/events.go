@@ -0,0 +1,115 @@
+package task
+
+import (
+	"TaskService/internal/model"
+	"TaskService/internal/service/event"
+	"TaskService/internal/tenant"
+	"TaskService/pkg/events"
+	"TaskService/pkg/kafka"
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// publish отправляет в Kafka событие typ о задаче и возвращает его ID, prevStatus — статус
+// до изменения для task.updated и task.completed. Контекст трассировки в заголовки добавляет kafka.
+func (s *service) publish(ctx context.Context, typ string, task model.Task, prevStatus string) (string, error) {
+	e := events.New(typ, events.Task{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		CreatedBy:   task.CreatedBy,
+		Assignee:    task.Assignee,
+		TenantID:    task.TenantID,
+	})
+	e.PreviousStatus = prevStatus
+
+	value, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.kc.SendMessage(ctx, kafka.Message{
+		Key:   e.Key(),
+		Value: value,
+		Headers: map[string]string{
+			events.HeaderContentType: events.ContentType,
+			events.HeaderType:        e.Type,
+			tenant.KafkaHeader:       task.TenantID,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return e.ID, nil
+}
+
+// busEventID ID события подписчиков Subscribe. Одно событие Kafka дает до двух событий подписчиков,
+// поэтому к ID события Kafka добавляется тип. Все реплики получают одинаковые ID.
+func busEventID(id string, typ event.Type) string {
+	return id + ":" + string(typ)
+}
+
+// createdTaskID возвращает ID задачи из события task.created. ok ложно для остальных событий,
+// которые обработчику не нужны. Сообщения прежнего формата (ID числом) еще могут оставаться
+// в топике после обновления и считаются task.created.
+func createdTaskID(value []byte) (id int, ok bool, err error) {
+	if id, err := strconv.Atoi(string(value)); err == nil {
+		return id, true, nil
+	}
+
+	e, err := events.Parse(value)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if e.Type != events.TypeTaskCreated {
+		return 0, false, nil
+	}
+
+	return e.Data.ID, true, nil
+}
+
+// busEvents переводит событие Kafka в события подписчиков Subscribe: task.created — в created,
+// task.updated — в updated и, если изменился статус, status_changed. task.completed повторяет
+// status_changed и, как и сообщения прежнего формата, не дает событий.
+func busEvents(value []byte) ([]event.Event, error) {
+	if _, err := strconv.Atoi(string(value)); err == nil {
+		return nil, nil
+	}
+
+	e, err := events.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	task := model.Task{
+		ID:          e.Data.ID,
+		Title:       e.Data.Title,
+		Description: e.Data.Description,
+		Status:      e.Data.Status,
+		CreatedBy:   e.Data.CreatedBy,
+		Assignee:    e.Data.Assignee,
+		TenantID:    e.Data.TenantID,
+	}
+
+	var types []event.Type
+	switch e.Type {
+	case events.TypeTaskCreated:
+		types = []event.Type{event.TypeCreated}
+	case events.TypeTaskUpdated:
+		types = []event.Type{event.TypeUpdated}
+		if e.PreviousStatus != task.Status {
+			types = append(types, event.TypeStatusChanged)
+		}
+	}
+
+	result := make([]event.Event, 0, len(types))
+	for _, typ := range types {
+		result = append(result, event.Event{ID: busEventID(e.ID, typ), Type: typ, Task: task})
+	}
+
+	return result, nil
+}
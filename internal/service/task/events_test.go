@@ -0,0 +1,84 @@
+package task
+
+import (
+	"encoding/json"
+	"testing"
+
+	"TaskService/internal/service/event"
+	"TaskService/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatedTaskID(t *testing.T) {
+	value := func(typ string) []byte {
+		data, err := json.Marshal(events.New(typ, events.Task{ID: 42}))
+		require.NoError(t, err)
+		return data
+	}
+
+	id, ok, err := createdTaskID(value(events.TypeTaskCreated))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 42, id)
+
+	_, ok, err = createdTaskID(value(events.TypeTaskCompleted))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// сообщения прежнего формата
+	id, ok, err = createdTaskID([]byte("7"))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 7, id)
+
+	_, _, err = createdTaskID([]byte(`{"specversion":"0.3","type":"task.created"}`))
+	assert.ErrorIs(t, err, events.ErrSpecVersion)
+}
+
+func TestBusEvents(t *testing.T) {
+	value := func(typ, prevStatus, status string) []byte {
+		e := events.New(typ, events.Task{ID: 42, Status: status, TenantID: "sales"})
+		e.ID = "ce-1"
+		e.PreviousStatus = prevStatus
+
+		data, err := json.Marshal(e)
+		require.NoError(t, err)
+		return data
+	}
+
+	tests := []struct {
+		name  string
+		value []byte
+		types []event.Type
+	}{
+		{name: "created", value: value(events.TypeTaskCreated, "", "created"), types: []event.Type{event.TypeCreated}},
+		{name: "updated", value: value(events.TypeTaskUpdated, "created", "created"), types: []event.Type{event.TypeUpdated}},
+		{
+			name:  "status changed",
+			value: value(events.TypeTaskUpdated, "created", "done"),
+			types: []event.Type{event.TypeUpdated, event.TypeStatusChanged},
+		},
+		{name: "completed", value: value(events.TypeTaskCompleted, "created", "done")},
+		{name: "legacy", value: []byte("7")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			published, err := busEvents(tt.value)
+			require.NoError(t, err)
+			require.Len(t, published, len(tt.types))
+
+			for i, e := range published {
+				assert.Equal(t, tt.types[i], e.Type)
+				assert.Equal(t, "ce-1:"+string(e.Type), e.ID)
+				assert.Equal(t, 42, e.Task.ID)
+				assert.Equal(t, "sales", e.Task.TenantID)
+			}
+		})
+	}
+
+	_, err := busEvents([]byte(`{"specversion":"0.3"}`))
+	assert.ErrorIs(t, err, events.ErrSpecVersion)
+}
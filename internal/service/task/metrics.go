@@ -0,0 +1,29 @@
+package task
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	tasksCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tasks_created_total",
+		Help: "Number of created tasks.",
+	})
+
+	tasksProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tasks_processed_total",
+		Help: "Number of tasks processed by the worker.",
+	})
+
+	tasksFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tasks_failed_total",
+		Help: "Number of task messages the worker failed to process.",
+	})
+
+	processingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "task_processing_duration_seconds",
+		Help:    "Duration of processing a single task message.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
@@ -0,0 +1,51 @@
+package task
+
+import "sync"
+
+// pool ограничивает число одновременно обрабатываемых сообщений. Размер меняется на лету:
+// при уменьшении уже запущенные обработчики доработают, новые дождутся освободившегося места.
+type pool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	size   int
+	active int
+}
+
+func newPool(size int) *pool {
+	result := &pool{
+		size: size,
+	}
+	result.cond = sync.NewCond(&result.mu)
+
+	return result
+}
+
+// Do дожидается свободного места и выполняет fn.
+func (p *pool) Do(fn func()) {
+	p.mu.Lock()
+	for p.active >= p.size {
+		p.cond.Wait()
+	}
+	p.active++
+	p.mu.Unlock()
+
+	defer p.release()
+
+	fn()
+}
+
+func (p *pool) release() {
+	p.mu.Lock()
+	p.active--
+	p.mu.Unlock()
+
+	p.cond.Signal()
+}
+
+func (p *pool) Resize(size int) {
+	p.mu.Lock()
+	p.size = size
+	p.mu.Unlock()
+
+	p.cond.Broadcast()
+}
@@ -0,0 +1,47 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPool_Resize(t *testing.T) {
+	p := newPool(1)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	work := func() {
+		entered <- struct{}{}
+		<-release
+	}
+
+	done := make(chan struct{}, 2)
+	run := func() {
+		p.Do(work)
+		done <- struct{}{}
+	}
+
+	go run()
+	<-entered
+
+	go run()
+
+	select {
+	case <-entered:
+		t.Fatal("second task started while the pool is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	p.Resize(2)
+
+	select {
+	case <-entered:
+	case <-time.After(time.Second):
+		t.Fatal("second task not started after resize")
+	}
+
+	close(release)
+	<-done
+	<-done
+}
@@ -1,47 +1,98 @@
 package task
 
 import (
+	"TaskService/internal/auth"
 	"TaskService/internal/dto"
 	"TaskService/internal/model"
+	"TaskService/internal/service/event"
 	"TaskService/internal/storage"
+	"TaskService/internal/tenant"
+	"TaskService/pkg/events"
 	"TaskService/pkg/kafka"
 	"TaskService/pkg/logger"
+	"TaskService/pkg/retry"
 	"context"
-	"encoding/json"
+	"database/sql"
 	"errors"
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/trace"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
-var ErrInvalidStatus = errors.New("invalid status")
+var (
+	ErrInvalidStatus   = errors.New("invalid status")
+	ErrInvalidAssignee = errors.New("invalid assignee")
+	ErrForbidden       = errors.New("forbidden")
+	ErrQuotaExceeded   = errors.New("task quota exceeded")
+)
+
+const (
+	statusCreated = "created"
+	statusDone    = "done"
+
+	// assigneeMe подставляется субъектом текущего запроса
+	assigneeMe = "me"
+)
 
 type Service interface {
 	Get(ctx context.Context, id int) (dto.GetTaskResponse, error)
-	GetList(ctx context.Context) (dto.GetTaskListResponse, error)
+	GetList(ctx context.Context, req dto.GetTaskListRequest) (dto.GetTaskListResponse, error)
 	Update(ctx context.Context, req dto.UpdateTaskRequest) error
 	Create(ctx context.Context, req dto.CreateTaskRequest) error
-	ProcessTasks()
+	Subscribe(ctx context.Context, filter event.Filter, lastEventID string) (event.Subscription, error)
+	// ProcessTasks обрабатывает сообщения Kafka до отмены ctx и возвращается после
+	// завершения обработки уже полученных задач. Смещение сообщения сохраняется после обработки.
+	ProcessTasks(ctx context.Context) error
+	// ConsumeEvents передает подписчикам Subscribe события задач из Kafka до отмены ctx, чтобы
+	// они видели изменения, сделанные другими процессами. После вызова изменения этого процесса
+	// тоже приходят подписчикам только через Kafka, без повторов.
+	ConsumeEvents(ctx context.Context) error
+	// SetConfig применяет настройки без перезапуска: квоты, число обработчиков, таймаут и повторы.
+	SetConfig(cfg Config)
 }
 
 type service struct {
-	st storage.Storage
-	kc kafka.Kafka
+	st   storage.Storage
+	kc   kafka.Kafka
+	bus  event.Bus
+	cfg  atomic.Pointer[Config]
+	pool *pool
+	// remote выставляется ConsumeEvents: события в bus публикуются только из Kafka
+	remote atomic.Bool
 }
 
-func New(st storage.Storage, kc kafka.Kafka) Service {
+func New(st storage.Storage, kc kafka.Kafka, bus event.Bus, cfg Config) Service {
 	result := &service{
-		st: st,
-		kc: kc,
+		st:   st,
+		kc:   kc,
+		bus:  bus,
+		pool: newPool(cfg.workers()),
 	}
+	result.cfg.Store(&cfg)
 
 	return result
 }
 
+func (s *service) SetConfig(cfg Config) {
+	s.cfg.Store(&cfg)
+	s.pool.Resize(cfg.workers())
+}
+
+func (s *service) config() *Config {
+	return s.cfg.Load()
+}
+
 func (s *service) Get(ctx context.Context, id int) (dto.GetTaskResponse, error) {
 	var resp dto.GetTaskResponse
 
-	log := logger.Get()
+	log := logger.FromContext(ctx)
+
+	subject, role, err := access(ctx)
+	if err != nil {
+		return resp, err
+	}
 
 	task, err := s.st.DB().Get(ctx, id)
 
@@ -50,81 +101,181 @@ func (s *service) Get(ctx context.Context, id int) (dto.GetTaskResponse, error)
 		return resp, err
 	}
 
-	resp = dto.GetTaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      task.Status,
+	if !canAccess(subject, role, task) {
+		return resp, sql.ErrNoRows
 	}
 
-	return resp, nil
+	return dto.NewGetTaskResponse(task), nil
 }
 
-func (s *service) GetList(ctx context.Context) (dto.GetTaskListResponse, error) {
+func (s *service) GetList(ctx context.Context, req dto.GetTaskListRequest) (dto.GetTaskListResponse, error) {
 	resp := dto.GetTaskListResponse{
 		Tasks: make([]dto.GetTaskResponse, 0),
 	}
 
-	log := logger.Get()
+	log := logger.FromContext(ctx)
+
+	subject, role, err := access(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	assignee, err := resolveAssignee(subject, req.Assignee)
+	if err != nil {
+		return resp, err
+	}
+
+	filter := model.TaskFilter{
+		Status:   req.Status,
+		Assignee: assignee,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+
+	if role != auth.RoleAdmin {
+		filter.VisibleTo = subject
+	}
 
-	tasks, err := s.st.DB().GetList(ctx)
+	tasks, err := s.st.DB().GetList(ctx, filter)
 	if err != nil {
 		log.Info().Err(err).Msg("get tasks failed")
 		return resp, err
 	}
 
 	for _, task := range tasks {
-		simpleTask := dto.GetTaskResponse{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			Status:      task.Status,
-		}
-
-		resp.Tasks = append(resp.Tasks, simpleTask)
+		resp.Tasks = append(resp.Tasks, dto.NewGetTaskResponse(task))
 	}
 
 	return resp, nil
 }
 
 func (s *service) Update(ctx context.Context, req dto.UpdateTaskRequest) error {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 
 	if err := validateStatus(req.Status); err != nil {
 		log.Error().Err(err).Msg("validateStatus failed")
 		return err
 	}
 
+	subject, role, err := access(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !role.CanWrite() {
+		return ErrForbidden
+	}
+
 	tx, err := s.st.DB().BeginTx(ctx)
 	if err != nil {
 		log.Info().Err(err).Msg("begin tx failed")
+		return err
 	}
 	defer tx.Rollback()
 
+	// строка заблокирована до конца транзакции: параллельное изменение дождется ее и увидит новый статус
+	prev, err := s.st.DB().GetForUpdate(ctx, tx, req.ID)
+	if err != nil {
+		log.Info().Err(err).Msg("get task failed")
+		return err
+	}
+
+	if !canAccess(subject, role, prev) {
+		return sql.ErrNoRows
+	}
+
+	assignee := prev.Assignee
+	if req.Assignee != nil {
+		if assignee, err = resolveAssignee(subject, *req.Assignee); err != nil {
+			return err
+		}
+	}
+
 	task := model.Task{
 		ID:          req.ID,
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      req.Status,
+		CreatedBy:   prev.CreatedBy,
+		Assignee:    assignee,
+		TenantID:    prev.TenantID,
 	}
 
 	err = s.st.DB().Update(ctx, tx, task)
 	if err != nil {
 		log.Info().Err(err).Msg("update task failed")
+		return err
+	}
+
+	eventID, err := s.publish(ctx, events.TypeTaskUpdated, task, prev.Status)
+	if err != nil {
+		log.Info().Err(err).Msg("send message failed")
+		return err
+	}
+
+	if prev.Status != statusDone && task.Status == statusDone {
+		if _, err := s.publish(ctx, events.TypeTaskCompleted, task, prev.Status); err != nil {
+			log.Info().Err(err).Msg("send message failed")
+			return err
+		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.notify(eventID, event.TypeUpdated, task)
+	if prev.Status != task.Status {
+		s.notify(eventID, event.TypeStatusChanged, task)
+	}
+
+	return nil
 }
 
 func (s *service) Create(ctx context.Context, req dto.CreateTaskRequest) error {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
+
+	subject, role, err := access(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !role.CanWrite() {
+		return ErrForbidden
+	}
+
+	assignee, err := resolveAssignee(subject, req.Assignee)
+	if err != nil {
+		return err
+	}
 
 	tx, err := s.st.DB().BeginTx(ctx)
+	if err != nil {
+		log.Info().Err(err).Msg("begin tx failed")
+		return err
+	}
 	defer tx.Rollback()
 
+	tenantID := tenant.FromContext(ctx)
+
+	if limit := s.config().quota(tenantID); limit > 0 {
+		count, err := s.st.DB().Count(ctx, tx)
+		if err != nil {
+			log.Info().Err(err).Msg("count tasks failed")
+			return err
+		}
+
+		if count >= limit {
+			return ErrQuotaExceeded
+		}
+	}
+
 	task := model.Task{
 		Title:       req.Title,
 		Description: req.Description,
+		CreatedBy:   subject,
+		Assignee:    assignee,
+		TenantID:    tenantID,
 	}
 
 	id, err := s.st.DB().Create(ctx, tx, task)
@@ -133,66 +284,225 @@ func (s *service) Create(ctx context.Context, req dto.CreateTaskRequest) error {
 		return err
 	}
 
-	message, err := json.Marshal(id)
+	task.ID = id
+	task.Status = statusCreated
+
+	eventID, err := s.publish(ctx, events.TypeTaskCreated, task, "")
 	if err != nil {
-		log.Info().Err(err).Msg("create task failed")
+		log.Info().Err(err).Msg("send message failed")
 		return err
 	}
 
-	err = s.kc.SendMessage(message)
-	if err != nil {
-		log.Info().Err(err).Msg("send message failed")
+	if err := tx.Commit(); err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	tasksCreated.Inc()
+
+	s.notify(eventID, event.TypeCreated, task)
+
+	return nil
+}
+
+func (s *service) Subscribe(ctx context.Context, filter event.Filter, lastEventID string) (event.Subscription, error) {
+	subject, role, err := access(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != auth.RoleAdmin {
+		filter.Subject = subject
+	}
+
+	filter.Tenant = tenant.FromContext(ctx)
+
+	return s.bus.Subscribe(filter, lastEventID), nil
 }
 
-func (s *service) ProcessTasks() {
-	handler := func(message *sarama.ConsumerMessage) {
-		var id int
-		log := logger.Get()
-		if err := json.Unmarshal(message.Value, &id); err != nil {
-			return
-		}
+// notify передает событие подписчикам Subscribe, если они не получают события из Kafka.
+// id — ID события Kafka, как и в ConsumeEvents.
+func (s *service) notify(id string, typ event.Type, task model.Task) {
+	if !s.remote.Load() {
+		s.bus.Publish(busEventID(id, typ), typ, task)
+	}
+}
+
+func (s *service) ConsumeEvents(ctx context.Context) error {
+	s.remote.Store(true)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	log := logger.FromContext(ctx)
 
-		task, err := s.st.DB().Get(ctx, id)
+	return s.kc.ConsumeAll(ctx, func(message *sarama.ConsumerMessage) {
+		published, err := busEvents(message.Value)
 		if err != nil {
+			log.Info().Err(err).Msg("invalid task event")
 			return
 		}
 
-		log.Info().
-			Str("id", strconv.Itoa(id)).
-			Str("title", task.Title).
-			Str("description", task.Description).
-			Msg("process task")
-
-		if task.Status != "" {
-			updateReq := dto.UpdateTaskRequest{
-				ID:          task.ID,
-				Title:       task.Title,
-				Description: task.Description,
-				Status:      "done",
-			}
-			if err := s.Update(ctx, updateReq); err != nil {
-				log.Info().Err(err).Msg("update task failed")
-			} else {
-				log.Info().Msg("success")
-			}
+		for _, e := range published {
+			s.bus.Publish(e.ID, e.Type, e.Task)
 		}
+	})
+}
+
+func (s *service) ProcessTasks(ctx context.Context) error {
+	// партиции читаются параллельно, а pool ограничивает число одновременно обрабатываемых сообщений
+	return s.kc.ConsumeMessages(ctx, func(message *sarama.ConsumerMessage) {
+		s.pool.Do(func() {
+			s.process(message)
+		})
+	})
+}
+
+func (s *service) process(message *sarama.ConsumerMessage) {
+	start := time.Now()
+	defer func() {
+		processingDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	cfg := s.config()
+
+	// задачи из Kafka обрабатываются от имени сервиса, без Principal
+	ctx, cancel := context.WithTimeout(auth.WithTrusted(context.Background()), cfg.processTimeout())
+	defer cancel()
+
+	if tenantID := kafka.Header(message, tenant.KafkaHeader); tenantID != "" {
+		ctx = tenant.WithID(ctx, tenantID)
+	}
+
+	ctx, span := startProcess(ctx, message)
+	defer span.End()
+
+	ctx = messageLogger(ctx, message)
+	log := logger.FromContext(ctx)
+
+	id, ok, err := createdTaskID(message.Value)
+	if err != nil {
+		tasksFailed.Inc()
+		log.Info().Err(err).Msg("invalid task message")
+		return
+	}
+
+	// task.updated и task.completed, в том числе отправленные самим обработчиком, пропускаются
+	if !ok {
+		return
 	}
 
-	go func() {
-		if err := s.kc.ConsumeMessages(handler); err != nil {
+	var task model.Task
+
+	// task.created отправляется до фиксации транзакции создания, и задача может быть еще не видна:
+	// ErrNoRows здесь повторяется в пределах cfg.Retry
+	err = retry.Do(ctx, "get task", cfg.Retry, func(ctx context.Context) (err error) {
+		task, err = s.st.DB().Get(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return err
 		}
-	}()
+		return retryable(err)
+	})
+	if err != nil {
+		tasksFailed.Inc()
+		log.Info().Err(err).Msg("get task failed")
+		return
+	}
+
+	// по записи на сообщение: прореживаются настройками LOGGER_SAMPLE_*, title и description
+	// маскируются LOGGER_REDACT
+	sampled := logger.Sampled(log)
+
+	sampled.Info().
+		Str("id", strconv.Itoa(id)).
+		Str("title", task.Title).
+		Str("description", task.Description).
+		Msg("process task")
+
+	if task.Status != "" {
+		updateReq := dto.UpdateTaskRequest{
+			ID:          task.ID,
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      statusDone,
+		}
+
+		err := retry.Do(ctx, "update task", cfg.Retry, func(ctx context.Context) error {
+			return retryable(s.Update(ctx, updateReq))
+		})
+		if err != nil {
+			tasksFailed.Inc()
+			log.Info().Err(err).Msg("update task failed")
+		} else {
+			tasksProcessed.Inc()
+			sampled.Info().Msg("success")
+		}
+	}
+}
+
+// retryable помечает ошибки, которые повтор не исправит: задача удалена или запрос к ней неверен.
+func retryable(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows),
+		errors.Is(err, ErrForbidden),
+		errors.Is(err, ErrInvalidStatus),
+		errors.Is(err, ErrInvalidAssignee),
+		errors.Is(err, ErrQuotaExceeded):
+		return retry.Permanent(err)
+	}
+
+	return err
+}
+
+// messageLogger кладет в контекст логгер с координатами сообщения, тенантом и trace_id.
+func messageLogger(ctx context.Context, message *sarama.ConsumerMessage) context.Context {
+	lctx := logger.FromContext(ctx).With().
+		Str("topic", message.Topic).
+		Int32("partition", message.Partition).
+		Int64("offset", message.Offset).
+		Str("tenant_id", tenant.FromContext(ctx))
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		lctx = lctx.Str("trace_id", sc.TraceID().String())
+	}
+
+	return logger.WrapToContext(ctx, lctx.Logger())
+}
+
+// access возвращает субъект и роль вызова. Без Principal роль admin получает только доверенный
+// вызов (auth.WithTrusted), остальным доступ запрещен.
+func access(ctx context.Context) (string, auth.Role, error) {
+	p, ok := auth.FromContext(ctx)
+	if ok {
+		role := p.Role()
+		if !role.CanRead() {
+			return "", "", ErrForbidden
+		}
+
+		return p.Subject, role, nil
+	}
+
+	if auth.Trusted(ctx) {
+		return "", auth.RoleAdmin, nil
+	}
+
+	return "", "", ErrForbidden
+}
+
+func canAccess(subject string, role auth.Role, task model.Task) bool {
+	return role == auth.RoleAdmin || task.CreatedBy == subject || task.Assignee == subject
+}
+
+func resolveAssignee(subject, assignee string) (string, error) {
+	if assignee != assigneeMe {
+		return assignee, nil
+	}
+
+	if subject == "" {
+		return "", ErrInvalidAssignee
+	}
+
+	return subject, nil
 }
 
 func validateStatus(status string) error {
-	if status == "done" || status == "created" {
+	if status == statusDone || status == statusCreated {
 		return nil
 	}
 	return ErrInvalidStatus
@@ -0,0 +1,30 @@
+package task
+
+import (
+	"TaskService/pkg/kafka"
+	"context"
+	"strconv"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("TaskService/internal/service/task")
+
+// startProcess открывает спан обработки сообщения, связанный со спаном отправки из заголовков.
+func startProcess(ctx context.Context, message *sarama.ConsumerMessage) (context.Context, trace.Span) {
+	producer := trace.SpanContextFromContext(kafka.Extract(context.Background(), message))
+
+	return tracer.Start(ctx, "process task",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(trace.Link{SpanContext: producer}),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(message.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(message.Partition))),
+			semconv.MessagingKafkaMessageOffset(int(message.Offset)),
+		),
+	)
+}
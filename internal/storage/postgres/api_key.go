@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"TaskService/internal/model"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type APIKeyStorage interface {
+	Create(ctx context.Context, key model.APIKey) (model.APIKey, error)
+	// List возвращает ключи тенанта tenantID, пустой tenantID — ключи всех тенантов.
+	List(ctx context.Context, tenantID string) ([]model.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (model.APIKey, error)
+	// Revoke отзывает ключ тенанта tenantID, пустой tenantID — ключ любого тенанта.
+	Revoke(ctx context.Context, id int, tenantID string) error
+	Touch(ctx context.Context, id int, usedAt time.Time) error
+}
+
+type apiKeyRow struct {
+	ID         int            `db:"id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	TenantID   string         `db:"tenant_id"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+}
+
+func (r apiKeyRow) toModel() model.APIKey {
+	return model.APIKey{
+		ID:         r.ID,
+		Name:       r.Name,
+		Prefix:     r.Prefix,
+		KeyHash:    r.KeyHash,
+		Scopes:     r.Scopes,
+		TenantID:   r.TenantID,
+		CreatedAt:  r.CreatedAt,
+		ExpiresAt:  r.ExpiresAt,
+		LastUsedAt: r.LastUsedAt,
+		RevokedAt:  r.RevokedAt,
+	}
+}
+
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, tenant_id, created_at, expires_at, last_used_at, revoked_at"
+
+type apiKeyRepo struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyStorage(db *sqlx.DB) APIKeyStorage {
+	result := &apiKeyRepo{
+		db: db,
+	}
+
+	return result
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	defer observe("api_keys.create", time.Now())
+
+	var row apiKeyRow
+
+	query := "INSERT INTO api_keys (name, prefix, key_hash, scopes, tenant_id, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING " + apiKeyColumns
+
+	err := r.db.GetContext(ctx, &row, query, key.Name, key.Prefix, key.KeyHash, pq.StringArray(key.Scopes), key.TenantID, key.ExpiresAt)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	return row.toModel(), nil
+}
+
+func (r *apiKeyRepo) List(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	defer observe("api_keys.list", time.Now())
+
+	var rows []apiKeyRow
+
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE ($1 = '' OR tenant_id = $1) ORDER BY id"
+
+	if err := r.db.SelectContext(ctx, &rows, query, tenantID); err != nil {
+		return nil, err
+	}
+
+	keys := make([]model.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.toModel())
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepo) GetByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	defer observe("api_keys.get_by_hash", time.Now())
+
+	var row apiKeyRow
+
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1"
+
+	if err := r.db.GetContext(ctx, &row, query, hash); err != nil {
+		return model.APIKey{}, err
+	}
+
+	return row.toModel(), nil
+}
+
+// Revoke возвращает sql.ErrNoRows, если ключа нет, он уже отозван или принадлежит другому тенанту.
+func (r *apiKeyRepo) Revoke(ctx context.Context, id int, tenantID string) error {
+	defer observe("api_keys.revoke", time.Now())
+
+	query := "UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL AND ($2 = '' OR tenant_id = $2)"
+
+	res, err := r.db.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepo) Touch(ctx context.Context, id int, usedAt time.Time) error {
+	defer observe("api_keys.touch", time.Now())
+
+	query := "UPDATE api_keys SET last_used_at = $1 WHERE id = $2"
+
+	_, err := r.db.ExecContext(ctx, query, usedAt, id)
+
+	return err
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"TaskService/internal/model"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyStorage_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	storage := NewAPIKeyStorage(sqlx.NewDb(db, "sqlmock"))
+
+	ctx := context.Background()
+	key := model.APIKey{Name: "batch", Prefix: "tsk_1", KeyHash: "hash", Scopes: []string{"tasks:read"}, TenantID: "sales"}
+	createdAt := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "prefix", "key_hash", "scopes", "tenant_id", "created_at", "expires_at", "last_used_at", "revoked_at"}).
+		AddRow(1, key.Name, key.Prefix, key.KeyHash, "{tasks:read}", key.TenantID, createdAt, nil, nil, nil)
+
+	mock.ExpectQuery("INSERT INTO api_keys \\(name, prefix, key_hash, scopes, tenant_id, expires_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6\\) RETURNING").
+		WithArgs(key.Name, key.Prefix, key.KeyHash, pq.StringArray(key.Scopes), key.TenantID, key.ExpiresAt).
+		WillReturnRows(rows)
+
+	result, err := storage.Create(ctx, key)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+	assert.Equal(t, []string{"tasks:read"}, result.Scopes)
+	assert.Equal(t, "sales", result.TenantID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyStorage_Revoke_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	storage := NewAPIKeyStorage(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec("UPDATE api_keys SET revoked_at = now\\(\\) WHERE id = \\$1 AND revoked_at IS NULL AND \\(\\$2 = '' OR tenant_id = \\$2\\)").
+		WithArgs(1, "hr").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = storage.Revoke(context.Background(), 1, "hr")
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyStorage_List_Tenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	storage := NewAPIKeyStorage(sqlx.NewDb(db, "sqlmock"))
+
+	rows := sqlmock.NewRows([]string{"id", "name", "prefix", "key_hash", "scopes", "tenant_id", "created_at", "expires_at", "last_used_at", "revoked_at"}).
+		AddRow(1, "batch", "tsk_1", "hash", "{tasks:read}", "hr", time.Now(), nil, nil, nil)
+
+	mock.ExpectQuery("SELECT .+ FROM api_keys WHERE \\(\\$1 = '' OR tenant_id = \\$1\\) ORDER BY id").
+		WithArgs("hr").
+		WillReturnRows(rows)
+
+	keys, err := storage.List(context.Background(), "hr")
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,13 @@
 package postgres
 
+import "time"
+
 type Config struct {
 	URL    string
 	Driver string
+	// Пул соединений sql.DB. Нулевые значения оставляют настройки database/sql по умолчанию.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
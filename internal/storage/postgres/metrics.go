@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "Duration of storage calls by query name.",
+	Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"query"})
+
+// observe записывает длительность запроса: defer observe("tasks.get", time.Now()).
+func observe(query string, start time.Time) {
+	queryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+}
@@ -2,18 +2,26 @@ package postgres
 
 import (
 	"TaskService/internal/model"
+	"TaskService/internal/tenant"
 	"TaskService/pkg/logger"
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 )
 
 type Storage interface {
 	Get(ctx context.Context, id int) (model.Task, error)
-	GetList(ctx context.Context) ([]model.Task, error)
+	// GetForUpdate читает задачу в транзакции tx и блокирует строку до ее конца.
+	GetForUpdate(ctx context.Context, tx Tx, id int) (model.Task, error)
+	GetList(ctx context.Context, filter model.TaskFilter) ([]model.Task, error)
 	Update(ctx context.Context, tx Tx, req model.Task) error
 	Create(ctx context.Context, tx Tx, task model.Task) (int, error)
+	// Count возвращает число задач тенанта и блокирует создание задач тенантом до конца транзакции.
+	Count(ctx context.Context, tx Tx) (int, error)
 	BeginTx(ctx context.Context) (Tx, error)
 }
 
@@ -28,71 +36,153 @@ type repo struct {
 	db *sqlx.DB
 }
 
-func New(cfg Config) (Storage, error) {
-	db, err := sqlx.Connect(cfg.Driver, cfg.URL)
+func Connect(ctx context.Context, cfg Config) (*sqlx.DB, error) {
+	db, err := sqlx.ConnectContext(ctx, cfg.Driver, cfg.URL)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &repo{
-		db: db,
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+
+	if cfg.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+
+	if cfg.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 	}
 
 	log := logger.Get()
 
 	log.Info().Msg("connect to postgres")
 
-	return result, nil
+	return db, nil
 }
 
-func (r *repo) Get(ctx context.Context, id int) (model.Task, error) {
-	var task model.Task
+func New(db *sqlx.DB) Storage {
+	result := &repo{
+		db: db,
+	}
+
+	return result
+}
+
+func (r *repo) Get(ctx context.Context, id int) (task model.Task, err error) {
+	ctx, end := startQuery(ctx, "tasks.get")
+	defer end(&err)
 
-	query := "SELECT * FROM tasks WHERE id = $1"
+	query := "SELECT * FROM tasks WHERE id = $1 AND tenant_id = $2"
 
-	err := r.db.GetContext(ctx, &task, query, id)
+	err = r.inTenant(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &task, query, id, tenant.FromContext(ctx))
+	})
 
 	return task, err
 }
 
-func (r *repo) GetList(ctx context.Context) ([]model.Task, error) {
-	var tasks []model.Task
+func (r *repo) GetForUpdate(ctx context.Context, tx Tx, id int) (task model.Task, err error) {
+	ctx, end := startQuery(ctx, "tasks.get_for_update")
+	defer end(&err)
 
-	query := "SELECT * FROM tasks"
+	query := "SELECT id, title, description, status, created_by, assignee, tenant_id FROM tasks WHERE id = $1 AND tenant_id = $2 FOR UPDATE"
 
-	rows, err := r.db.QueryxContext(ctx, query)
-	if err != nil {
-		return tasks, err
-	}
+	err = tx.QueryRowContext(ctx, query, id, tenant.FromContext(ctx)).
+		Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.CreatedBy, &task.Assignee, &task.TenantID)
 
-	for rows.Next() {
-		var task model.Task
+	return task, err
+}
+
+func (r *repo) GetList(ctx context.Context, filter model.TaskFilter) (tasks []model.Task, err error) {
+	ctx, end := startQuery(ctx, "tasks.list")
+	defer end(&err)
+
+	query, args := listQuery(tenant.FromContext(ctx), filter)
 
-		err = rows.StructScan(&task)
+	err = r.inTenant(ctx, func(tx *sqlx.Tx) error {
+		rows, err := tx.QueryxContext(ctx, query, args...)
 		if err != nil {
-			return tasks, err
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var task model.Task
+
+			if err := rows.StructScan(&task); err != nil {
+				return err
+			}
+
+			tasks = append(tasks, task)
 		}
 
-		tasks = append(tasks, task)
+		return rows.Err()
+	})
+
+	return tasks, err
+}
+
+func listQuery(tenantID string, filter model.TaskFilter) (string, []interface{}) {
+	var (
+		conditions = []string{"tenant_id = $1"}
+		args       = []interface{}{tenantID}
+	)
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	if filter.Assignee != "" {
+		args = append(args, filter.Assignee)
+		conditions = append(conditions, fmt.Sprintf("assignee = $%d", len(args)))
+	}
+
+	if filter.VisibleTo != "" {
+		args = append(args, filter.VisibleTo)
+		conditions = append(conditions, fmt.Sprintf("(created_by = $%d OR assignee = $%d)", len(args), len(args)))
 	}
 
-	return tasks, nil
+	query := "SELECT * FROM tasks WHERE " + strings.Join(conditions, " AND ")
+
+	query += " ORDER BY id"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args
 }
 
-func (r *repo) Update(ctx context.Context, tx Tx, req model.Task) error {
-	query := "UPDATE tasks SET title = $1, description = $2, status = $3 WHERE id = $4"
+func (r *repo) Update(ctx context.Context, tx Tx, req model.Task) (err error) {
+	ctx, end := startQuery(ctx, "tasks.update")
+	defer end(&err)
+
+	query := "UPDATE tasks SET title = $1, description = $2, status = $3, assignee = $4 WHERE id = $5 AND tenant_id = $6"
 
-	_, err := tx.ExecContext(ctx, query, req.Title, req.Description, req.Status, req.ID)
+	_, err = tx.ExecContext(ctx, query, req.Title, req.Description, req.Status, req.Assignee, req.ID, tenant.FromContext(ctx))
 
 	return err
 }
 
-func (r *repo) Create(ctx context.Context, tx Tx, req model.Task) (int, error) {
-	var id int
+func (r *repo) Create(ctx context.Context, tx Tx, req model.Task) (id int, err error) {
+	ctx, end := startQuery(ctx, "tasks.create")
+	defer end(&err)
 
-	query := "INSERT INTO tasks (title, description) VALUES ($1, $2) RETURNING id"
+	query := "INSERT INTO tasks (title, description, created_by, assignee, tenant_id) VALUES ($1, $2, $3, $4, $5) RETURNING id"
 
-	err := tx.QueryRowContext(ctx, query, req.Title, req.Description).Scan(&id)
+	err = tx.QueryRowContext(ctx, query, req.Title, req.Description, req.CreatedBy, req.Assignee, tenant.FromContext(ctx)).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -100,6 +190,55 @@ func (r *repo) Create(ctx context.Context, tx Tx, req model.Task) (int, error) {
 	return id, nil
 }
 
-func (r *repo) BeginTx(ctx context.Context) (Tx, error) {
-	return r.db.BeginTxx(ctx, nil)
+func (r *repo) Count(ctx context.Context, tx Tx) (count int, err error) {
+	ctx, end := startQuery(ctx, "tasks.count")
+	defer end(&err)
+
+	tenantID := tenant.FromContext(ctx)
+
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", tenantID); err != nil {
+		return 0, err
+	}
+
+	query := "SELECT count(*) FROM tasks WHERE tenant_id = $1"
+
+	err = tx.QueryRowContext(ctx, query, tenantID).Scan(&count)
+
+	return count, err
+}
+
+func (r *repo) BeginTx(ctx context.Context) (_ Tx, err error) {
+	ctx, end := startQuery(ctx, "tasks.begin")
+	defer end(&err)
+
+	return r.beginTenantTx(ctx)
+}
+
+// beginTenantTx открывает транзакцию с app.tenant_id, по которому работают политики RLS таблицы tasks.
+func (r *repo) beginTenantTx(ctx context.Context) (*sqlx.Tx, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('app.tenant_id', $1, true)", tenant.FromContext(ctx)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (r *repo) inTenant(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.beginTenantTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"TaskService/internal/model"
+	"TaskService/internal/tenant"
 	"context"
 	"testing"
 
@@ -10,6 +11,13 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func expectTenantTx(mock sqlmock.Sqlmock, tenantID string) {
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT set_config\\('app.tenant_id', \\$1, true\\)").
+		WithArgs(tenantID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
 func TestPostgresStorage_Get(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -31,9 +39,11 @@ func TestPostgresStorage_Get(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "title", "description", "status"}).
 		AddRow(expectedTask.ID, expectedTask.Title, expectedTask.Description, expectedTask.Status)
 
-	mock.ExpectQuery("SELECT \\* FROM tasks WHERE id = \\$1").
-		WithArgs(taskID).
+	expectTenantTx(mock, tenant.Default)
+	mock.ExpectQuery("SELECT \\* FROM tasks WHERE id = \\$1 AND tenant_id = \\$2").
+		WithArgs(taskID, tenant.Default).
 		WillReturnRows(rows)
+	mock.ExpectCommit()
 
 	result, err := storage.Get(ctx, taskID)
 
@@ -60,9 +70,13 @@ func TestPostgresStorage_GetList(t *testing.T) {
 		AddRow(expectedTasks[0].ID, expectedTasks[0].Title, expectedTasks[0].Description, expectedTasks[0].Status).
 		AddRow(expectedTasks[1].ID, expectedTasks[1].Title, expectedTasks[1].Description, expectedTasks[1].Status)
 
-	mock.ExpectQuery("SELECT \\* FROM tasks").WillReturnRows(rows)
+	expectTenantTx(mock, tenant.Default)
+	mock.ExpectQuery("SELECT \\* FROM tasks WHERE tenant_id = \\$1 ORDER BY id").
+		WithArgs(tenant.Default).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
 
-	result, err := storage.GetList(ctx)
+	result, err := storage.GetList(ctx, model.TaskFilter{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedTasks, result)
@@ -77,20 +91,22 @@ func TestPostgresStorage_Create(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	storage := &repo{db: sqlxDB}
 
-	ctx := context.Background()
+	ctx := tenant.WithID(context.Background(), "sales")
 	task := model.Task{
 		Title:       "New Task",
 		Description: "New Description",
+		CreatedBy:   "user-1",
+		Assignee:    "user-2",
 	}
 	expectedID := 1
 
-	mock.ExpectBegin()
+	expectTenantTx(mock, "sales")
 
 	tx, err := storage.BeginTx(ctx)
 	assert.NoError(t, err)
 
-	mock.ExpectQuery("INSERT INTO tasks \\(title, description\\) VALUES \\(\\$1, \\$2\\) RETURNING id").
-		WithArgs(task.Title, task.Description).
+	mock.ExpectQuery("INSERT INTO tasks \\(title, description, created_by, assignee, tenant_id\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id").
+		WithArgs(task.Title, task.Description, task.CreatedBy, task.Assignee, "sales").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
 
 	mock.ExpectCommit()
@@ -121,13 +137,13 @@ func TestPostgresStorage_Update(t *testing.T) {
 		Status:      "done",
 	}
 
-	mock.ExpectBegin()
+	expectTenantTx(mock, tenant.Default)
 
 	tx, err := storage.BeginTx(ctx)
 	assert.NoError(t, err)
 
-	mock.ExpectExec("UPDATE tasks SET title = \\$1, description = \\$2, status = \\$3 WHERE id = \\$4").
-		WithArgs(task.Title, task.Description, task.Status, task.ID).
+	mock.ExpectExec("UPDATE tasks SET title = \\$1, description = \\$2, status = \\$3, assignee = \\$4 WHERE id = \\$5 AND tenant_id = \\$6").
+		WithArgs(task.Title, task.Description, task.Status, task.Assignee, task.ID, tenant.Default).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -141,6 +157,38 @@ func TestPostgresStorage_Update(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestPostgresStorage_GetForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	storage := &repo{db: sqlxDB}
+
+	ctx := context.Background()
+
+	expectTenantTx(mock, tenant.Default)
+
+	tx, err := storage.BeginTx(ctx)
+	assert.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "created_by", "assignee", "tenant_id"}).
+		AddRow(1, "Task", "", "created", "alice", "bob", tenant.Default)
+
+	mock.ExpectQuery("SELECT id, title, description, status, created_by, assignee, tenant_id FROM tasks WHERE id = \\$1 AND tenant_id = \\$2 FOR UPDATE").
+		WithArgs(1, tenant.Default).
+		WillReturnRows(rows)
+
+	mock.ExpectRollback()
+
+	task, err := storage.GetForUpdate(ctx, tx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Task{ID: 1, Title: "Task", Status: "created", CreatedBy: "alice", Assignee: "bob", TenantID: tenant.Default}, task)
+
+	assert.NoError(t, tx.Rollback())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestPostgresStorage_BeginTx(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -151,7 +199,7 @@ func TestPostgresStorage_BeginTx(t *testing.T) {
 
 	ctx := context.Background()
 
-	mock.ExpectBegin()
+	expectTenantTx(mock, tenant.Default)
 
 	tx, err := storage.BeginTx(ctx)
 	assert.NoError(t, err)
@@ -164,3 +212,88 @@ func TestPostgresStorage_BeginTx(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestPostgresStorage_GetList_Filter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	storage := &repo{db: sqlxDB}
+
+	ctx := context.Background()
+	filter := model.TaskFilter{Status: "done", Limit: 10, Offset: 20}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "status"}).
+		AddRow(21, "Task 21", "Desc 21", "done")
+
+	expectTenantTx(mock, tenant.Default)
+	mock.ExpectQuery("SELECT \\* FROM tasks WHERE tenant_id = \\$1 AND status = \\$2 ORDER BY id LIMIT \\$3 OFFSET \\$4").
+		WithArgs(tenant.Default, filter.Status, filter.Limit, filter.Offset).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	result, err := storage.GetList(ctx, filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStorage_GetList_VisibleTo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	storage := &repo{db: sqlxDB}
+
+	ctx := context.Background()
+	filter := model.TaskFilter{Assignee: "user-2", VisibleTo: "user-1"}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "status", "created_by", "assignee"}).
+		AddRow(1, "Task 1", "Desc 1", "created", "user-1", "user-2")
+
+	expectTenantTx(mock, tenant.Default)
+	mock.ExpectQuery("SELECT \\* FROM tasks WHERE tenant_id = \\$1 AND assignee = \\$2 AND \\(created_by = \\$3 OR assignee = \\$3\\) ORDER BY id").
+		WithArgs(tenant.Default, filter.Assignee, filter.VisibleTo).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	result, err := storage.GetList(ctx, filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "user-1", result[0].CreatedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStorage_Count(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	storage := &repo{db: sqlxDB}
+
+	ctx := tenant.WithID(context.Background(), "sales")
+
+	expectTenantTx(mock, "sales")
+	mock.ExpectExec("SELECT pg_advisory_xact_lock\\(hashtext\\(\\$1\\)\\)").
+		WithArgs("sales").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM tasks WHERE tenant_id = \\$1").
+		WithArgs("sales").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectRollback()
+
+	tx, err := storage.BeginTx(ctx)
+	assert.NoError(t, err)
+
+	count, err := storage.Count(ctx, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.NoError(t, tx.Rollback())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"TaskService/internal/ratelimit"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RateLimitStorage общее для реплик хранилище бакетов rate limiting. Бакеты пополняются по часам
+// базы, а не реплики: расхождение часов реплик не добавляет и не отнимает токены.
+type RateLimitStorage interface {
+	Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error)
+}
+
+type rateLimitRepo struct {
+	db *sqlx.DB
+}
+
+func NewRateLimitStorage(db *sqlx.DB) RateLimitStorage {
+	result := &rateLimitRepo{
+		db: db,
+	}
+
+	return result
+}
+
+func (r *rateLimitRepo) Take(ctx context.Context, key string, limit ratelimit.Limit, _ time.Time) (ratelimit.Result, error) {
+	defer observe("rate_limits.take", time.Now())
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	defer tx.Rollback()
+
+	// Новый ключ начинается с полного бакета, FOR UPDATE сериализует реплики на одном ключе.
+	query := "INSERT INTO rate_limits (key, tokens, updated_at) VALUES ($1, $2, clock_timestamp()) ON CONFLICT (key) DO NOTHING"
+	if _, err := tx.ExecContext(ctx, query, key, float64(limit.Burst)); err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	var (
+		bucket ratelimit.Bucket
+		now    time.Time
+	)
+
+	// clock_timestamp(), а не now(): время после получения блокировки, а не начала транзакции
+	query = "SELECT tokens, updated_at, clock_timestamp() FROM rate_limits WHERE key = $1 FOR UPDATE"
+	if err := tx.QueryRowContext(ctx, query, key).Scan(&bucket.Tokens, &bucket.Updated, &now); err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	bucket, result := ratelimit.Take(bucket, limit, now)
+
+	query = "UPDATE rate_limits SET tokens = $1, updated_at = $2 WHERE key = $3"
+	if _, err := tx.ExecContext(ctx, query, bucket.Tokens, bucket.Updated, key); err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"TaskService/internal/ratelimit"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitStorage_Take(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	storage := NewRateLimitStorage(sqlx.NewDb(db, "sqlmock"))
+
+	limit := ratelimit.Limit{Rate: 1, Burst: 5}
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO rate_limits \\(key, tokens, updated_at\\) VALUES \\(\\$1, \\$2, clock_timestamp\\(\\)\\) ON CONFLICT \\(key\\) DO NOTHING").
+		WithArgs("write:ip:10.0.0.1", float64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT tokens, updated_at, clock_timestamp\\(\\) FROM rate_limits WHERE key = \\$1 FOR UPDATE").
+		WithArgs("write:ip:10.0.0.1").
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "updated_at", "clock_timestamp"}).AddRow(0.5, now, now))
+	mock.ExpectExec("UPDATE rate_limits SET tokens = \\$1, updated_at = \\$2 WHERE key = \\$3").
+		WithArgs(0.5, now, "write:ip:10.0.0.1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// часы реплики отстают на минуту: учитывается только время базы
+	result, err := storage.Take(context.Background(), "write:ip:10.0.0.1", limit, now.Add(-time.Minute))
+
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
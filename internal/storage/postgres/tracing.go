@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"TaskService/pkg/logger"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("TaskService/internal/storage/postgres")
+
+// startQuery открывает спан вызова хранилища. end записывает ошибку и длительность, пишет
+// вызов в debug-лог запроса и закрывает спан:
+//
+//	ctx, end := startQuery(ctx, "tasks.get")
+//	defer end(&err)
+func startQuery(ctx context.Context, query string) (context.Context, func(err *error)) {
+	start := time.Now()
+
+	ctx, span := tracer.Start(ctx, query,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(query)),
+	)
+
+	return ctx, func(err *error) {
+		log := logger.WithName(logger.FromContext(ctx), "postgres")
+
+		if *err != nil && !errors.Is(*err, sql.ErrNoRows) {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+
+		log.Debug().Err(*err).Str("query", query).Dur("duration", time.Since(start)).Msg("db query")
+
+		span.End()
+		observe(query, start)
+	}
+}
@@ -2,30 +2,68 @@ package storage
 
 import (
 	"TaskService/internal/storage/postgres"
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
 //go:generate mockery --name=Storage --dir=. --output=./mocks
 type Storage interface {
 	DB() postgres.Storage
-
+	APIKeys() postgres.APIKeyStorage
+	RateLimits() postgres.RateLimitStorage
+	Ping(ctx context.Context) error
+	Close() error
 }
 
 type repo struct {
-	psql postgres.Storage
+	db         *sqlx.DB
+	psql       postgres.Storage
+	apiKeys    postgres.APIKeyStorage
+	rateLimits postgres.RateLimitStorage
 }
 
 func (r *repo) DB() postgres.Storage {
 	return r.psql
 }
 
-func New(pcfg postgres.Config) (Storage, error) {
-	psql, err := postgres.New(pcfg)
+func (r *repo) APIKeys() postgres.APIKeyStorage {
+	return r.apiKeys
+}
+
+func (r *repo) RateLimits() postgres.RateLimitStorage {
+	return r.rateLimits
+}
+
+func (r *repo) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+func (r *repo) Close() error {
+	return r.db.Close()
+}
+
+func New(ctx context.Context, pcfg postgres.Config) (Storage, error) {
+	db, err := postgres.Connect(ctx, pcfg)
 	if err != nil {
 		return nil, err
 	}
 
+	// статистика пула из sql.DB.Stats(): открытые, занятые и простаивающие соединения, ожидания
+	err = prometheus.Register(collectors.NewDBStatsCollector(db.DB, "tasks"))
+	if are := (prometheus.AlreadyRegisteredError{}); err != nil && !errors.As(err, &are) {
+		db.Close()
+		return nil, err
+	}
+
 	result := &repo{
-		psql: psql,
+		db:         db,
+		psql:       postgres.New(db),
+		apiKeys:    postgres.NewAPIKeyStorage(db),
+		rateLimits: postgres.NewRateLimitStorage(db),
 	}
 
 	return result, nil
@@ -19,8 +19,13 @@ func (m *MockPostgresStorage) Get(ctx context.Context, id int) (model.Task, erro
 	return args.Get(0).(model.Task), args.Error(1)
 }
 
-func (m *MockPostgresStorage) GetList(ctx context.Context) ([]model.Task, error) {
-	args := m.Called(ctx)
+func (m *MockPostgresStorage) GetForUpdate(ctx context.Context, tx postgres.Tx, id int) (model.Task, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Get(0).(model.Task), args.Error(1)
+}
+
+func (m *MockPostgresStorage) GetList(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]model.Task), args.Error(1)
 }
 
@@ -34,6 +39,11 @@ func (m *MockPostgresStorage) Create(ctx context.Context, tx postgres.Tx, task m
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockPostgresStorage) Count(ctx context.Context, tx postgres.Tx) (int, error) {
+	args := m.Called(ctx, tx)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockPostgresStorage) BeginTx(ctx context.Context) (postgres.Tx, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(postgres.Tx), args.Error(1)
@@ -54,6 +64,6 @@ func TestNew(t *testing.T) {
 		Driver: "postgres",
 	}
 
-	_, err := New(cfg)
+	_, err := New(context.Background(), cfg)
 	assert.Error(t, err)
 }
@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"TaskService/internal/auth"
+	"context"
+	"errors"
+	"regexp"
+)
+
+const (
+	// Default тенант запросов без явного тенанта и строк, созданных до введения тенантов.
+	Default = "default"
+
+	Header = "X-Tenant-ID"
+	// KafkaHeader заголовок сообщения Kafka с тенантом задачи.
+	KafkaHeader = "tenant_id"
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+var (
+	ErrInvalid   = errors.New("invalid tenant")
+	ErrForbidden = errors.New("tenant not allowed")
+)
+
+type contextKey struct{}
+
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext возвращает тенант запроса или Default, если он не задан.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	return Default
+}
+
+// Resolve определяет тенант вызова по запрошенному requested (заголовок X-Tenant-ID или
+// метаданные x-tenant-id). Тенант из токена имеет приоритет, requested может лишь совпадать с ним.
+// Субъект без тенанта выбирает тенант только с ролью admin, вызов без субъекта — только
+// доверенный (auth.WithTrusted). Пустой результат выбора — Default.
+func Resolve(ctx context.Context, requested string) (string, error) {
+	if requested != "" && !Valid(requested) {
+		return "", ErrInvalid
+	}
+
+	principal, ok := auth.FromContext(ctx)
+
+	switch {
+	case ok && principal.Tenant != "":
+		if requested != "" && requested != principal.Tenant {
+			return "", ErrForbidden
+		}
+		return principal.Tenant, nil
+
+	case requested == "":
+		return Default, nil
+
+	case ok && principal.IsAdmin(), !ok && auth.Trusted(ctx):
+		return requested, nil
+
+	default:
+		return "", ErrForbidden
+	}
+}
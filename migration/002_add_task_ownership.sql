@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN assignee VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX tasks_created_by_idx ON tasks (created_by);
+CREATE INDEX tasks_assignee_idx ON tasks (assignee);
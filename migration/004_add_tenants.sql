@@ -0,0 +1,12 @@
+ALTER TABLE tasks ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+CREATE INDEX tasks_tenant_id_idx ON tasks (tenant_id, id);
+
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Сервис задаёт app.tenant_id в каждой транзакции. FORCE применяет политику и к владельцу таблицы.
+ALTER TABLE tasks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tasks FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tasks_tenant_isolation ON tasks
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,11 @@
+-- Бакеты rate limiting для RATELIMIT_BACKEND=postgres. Потеря данных при сбое не критична.
+CREATE UNLOGGED TABLE rate_limits (
+    key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX rate_limits_updated_at_idx ON rate_limits (updated_at);
+
+-- Давно не обновлявшиеся бакеты полностью восстановились, их можно удалять по расписанию:
+-- DELETE FROM rate_limits WHERE updated_at < now() - interval '1 hour';
@@ -0,0 +1,18 @@
+-- Роль, под которой работает сервис. Без SUPERUSER и BYPASSRLS: суперпользователь обходит
+-- row-level security из 004_add_tenants.sql даже с FORCE. Пароль задается при развертывании:
+-- ALTER ROLE task_service PASSWORD '...';
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'task_service') THEN
+        CREATE ROLE task_service LOGIN NOSUPERUSER NOBYPASSRLS NOCREATEDB NOCREATEROLE;
+    END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO task_service;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO task_service;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO task_service;
+
+-- таблицы следующих миграций
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO task_service;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO task_service;
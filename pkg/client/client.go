@@ -0,0 +1,280 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tenantHeader заголовок с тенантом запроса.
+const tenantHeader = "X-Tenant-ID"
+
+type ListOptions struct {
+	Status string
+	// Assignee фильтрует по исполнителю, "me" — текущий пользователь
+	Assignee string
+	Limit    int
+	Offset   int
+}
+
+type WatchOptions struct {
+	TaskIDs     []int
+	Status      string
+	LastEventID string
+}
+
+// Client HTTP-клиент Task API. Идемпотентные запросы (GET, PUT) повторяются
+// с экспоненциальной задержкой при сетевых ошибках, 5xx и 429.
+type Client struct {
+	cfg     Config
+	baseURL *url.URL
+}
+
+func New(cfg Config) (*Client, error) {
+	cfg = validateConfig(cfg)
+
+	baseURL, err := url.Parse(strings.TrimRight(cfg.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q", cfg.BaseURL)
+	}
+
+	result := &Client{
+		cfg:     cfg,
+		baseURL: baseURL,
+	}
+
+	return result, nil
+}
+
+func (c *Client) Get(ctx context.Context, id int) (Task, error) {
+	var task Task
+
+	err := c.do(ctx, http.MethodGet, "/tasks/"+strconv.Itoa(id), nil, nil, &task)
+
+	return task, err
+}
+
+func (c *Client) List(ctx context.Context, opts ListOptions) (TaskList, error) {
+	var list TaskList
+
+	query := url.Values{}
+	if opts.Status != "" {
+		query.Set("status", opts.Status)
+	}
+	if opts.Assignee != "" {
+		query.Set("assignee", opts.Assignee)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+
+	err := c.do(ctx, http.MethodGet, "/tasks", query, nil, &list)
+
+	return list, err
+}
+
+// ListAll постранично обходит все задачи. opts.Limit задает размер страницы.
+func (c *Client) ListAll(ctx context.Context, opts ListOptions) iter.Seq2[Task, error] {
+	return func(yield func(Task, error) bool) {
+		pageSize := opts.Limit
+		if pageSize <= 0 {
+			pageSize = c.cfg.PageSize
+		}
+
+		offset := opts.Offset
+
+		for {
+			page, err := c.List(ctx, ListOptions{Status: opts.Status, Assignee: opts.Assignee, Limit: pageSize, Offset: offset})
+			if err != nil {
+				yield(Task{}, err)
+				return
+			}
+
+			for _, task := range page.Tasks {
+				if !yield(task, nil) {
+					return
+				}
+			}
+
+			if len(page.Tasks) < pageSize {
+				return
+			}
+
+			offset += len(page.Tasks)
+		}
+	}
+}
+
+func (c *Client) Create(ctx context.Context, req CreateTaskRequest) error {
+	return c.do(ctx, http.MethodPost, "/tasks", nil, req, nil)
+}
+
+func (c *Client) Update(ctx context.Context, req UpdateTaskRequest) error {
+	return c.do(ctx, http.MethodPut, "/tasks", nil, req, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	idempotent := method == http.MethodGet || method == http.MethodPut
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, c.url(path, query), payload, nil)
+		if err == nil {
+			err = handleResponse(resp, out)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		if !idempotent || attempt >= c.cfg.MaxRetries || !retryable(ctx, err) {
+			return err
+		}
+
+		if err := sleep(ctx, c.backoff(attempt, err)); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, u string, payload []byte, header http.Header) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.setCredentials(req.Header)
+
+	return c.cfg.HTTPClient.Do(req)
+}
+
+func (c *Client) setCredentials(header http.Header) {
+	if c.cfg.Token != "" {
+		header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+	if c.cfg.Tenant != "" {
+		header.Set(tenantHeader, c.cfg.Tenant)
+	}
+}
+
+func (c *Client) url(path string, query url.Values) string {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func (c *Client) backoff(attempt int, err error) time.Duration {
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, c.cfg.RetryWaitMax)
+	}
+
+	wait := c.cfg.RetryWaitMin << attempt
+	if wait <= 0 || wait > c.cfg.RetryWaitMax {
+		wait = c.cfg.RetryWaitMax
+	}
+
+	return wait/2 + rand.N(wait/2+1)
+}
+
+func handleResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Code:       http.StatusText(resp.StatusCode),
+	}
+
+	var body errorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error != "" {
+		apiErr.Code = body.Error
+		apiErr.Message = body.Message
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return apiErr
+}
+
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
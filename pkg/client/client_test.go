@@ -0,0 +1,158 @@
+package client_test
+
+import (
+	"TaskService/internal/handler"
+	"TaskService/internal/service/event"
+	"TaskService/internal/service/servicetest"
+	"TaskService/pkg/client"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupClient(t *testing.T, h http.Handler) *client.Client {
+	t.Helper()
+
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(client.Config{
+		BaseURL:      server.URL,
+		RetryWaitMin: time.Millisecond,
+		RetryWaitMax: 5 * time.Millisecond,
+		PageSize:     2,
+	})
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestClient_CRUD(t *testing.T) {
+	c := setupClient(t, handler.New(servicetest.NewMemory(), handler.Config{}))
+	ctx := context.Background()
+
+	require.NoError(t, c.Create(ctx, client.CreateTaskRequest{Title: "Task 1", Description: "Desc 1"}))
+
+	got, err := c.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Task 1", got.Title)
+	assert.Equal(t, "created", got.Status)
+
+	require.NoError(t, c.Update(ctx, client.UpdateTaskRequest{ID: 1, Title: "Task 1", Status: "done"}))
+
+	list, err := c.List(ctx, client.ListOptions{Status: "done"})
+	require.NoError(t, err)
+	require.Len(t, list.Tasks, 1)
+	assert.Equal(t, "done", list.Tasks[0].Status)
+}
+
+func TestClient_Errors(t *testing.T) {
+	c := setupClient(t, handler.New(servicetest.NewMemory(), handler.Config{}))
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, 42)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	err = c.Create(ctx, client.CreateTaskRequest{})
+	assert.ErrorIs(t, err, client.ErrBadRequest)
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "Title is required", apiErr.Code)
+}
+
+func TestClient_RetriesOnServerError(t *testing.T) {
+	srv := servicetest.NewMemory()
+	router := handler.New(srv, handler.Config{})
+
+	var calls atomic.Int32
+	c := setupClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+
+	_, err := c.List(context.Background(), client.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+
+	calls.Store(0)
+	err = c.Create(context.Background(), client.CreateTaskRequest{Title: "Task"})
+	assert.ErrorIs(t, err, client.ErrServer)
+	assert.Equal(t, int32(1), calls.Load(), "POST must not be retried")
+}
+
+func TestClient_ListAll(t *testing.T) {
+	srv := servicetest.NewMemory()
+	c := setupClient(t, handler.New(srv, handler.Config{}))
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, c.Create(ctx, client.CreateTaskRequest{Title: "Task"}))
+	}
+
+	var ids []int
+	for task, err := range c.ListAll(ctx, client.ListOptions{}) {
+		require.NoError(t, err)
+		ids = append(ids, task.ID)
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+}
+
+func TestClient_Watch(t *testing.T) {
+	srv := servicetest.NewMemory()
+	c := setupClient(t, handler.New(srv, handler.Config{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, c.Create(ctx, client.CreateTaskRequest{Title: "Task 1"}))
+	require.NoError(t, c.Create(ctx, client.CreateTaskRequest{Title: "Task 2"}))
+
+	var events []client.TaskEvent
+	for e, err := range c.Watch(ctx, client.WatchOptions{LastEventID: "1"}) {
+		require.NoError(t, err)
+		events = append(events, e)
+
+		if len(events) == 1 {
+			require.NoError(t, c.Update(ctx, client.UpdateTaskRequest{ID: 2, Title: "Task 2", Status: "done"}))
+		}
+
+		if len(events) == 2 {
+			break
+		}
+	}
+
+	require.Len(t, events, 2)
+	assert.Equal(t, string(event.TypeCreated), events[0].Type)
+	assert.Equal(t, "Task 2", events[0].Task.Title)
+	assert.Equal(t, string(event.TypeUpdated), events[1].Type)
+	assert.Equal(t, "done", events[1].Task.Status)
+	assert.False(t, errors.Is(ctx.Err(), context.DeadlineExceeded))
+}
+
+func TestClient_WatchResync(t *testing.T) {
+	c := setupClient(t, handler.New(servicetest.NewMemory(), handler.Config{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for e, err := range c.Watch(ctx, client.WatchOptions{LastEventID: "unknown"}) {
+		require.NoError(t, err)
+		assert.Equal(t, client.EventResync, e.Type)
+		assert.Empty(t, e.ID)
+		assert.Nil(t, e.Task)
+		break
+	}
+}
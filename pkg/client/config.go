@@ -0,0 +1,52 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultMaxRetries   = 3
+	defaultRetryWaitMin = 200 * time.Millisecond
+	defaultRetryWaitMax = 5 * time.Second
+	defaultPageSize     = 100
+)
+
+type Config struct {
+	// BaseURL адрес сервиса, например http://localhost:3000
+	BaseURL string
+	// Token передается в заголовке Authorization: Bearer, если задан.
+	Token string
+	// Tenant передается в заголовке X-Tenant-ID, если задан.
+	Tenant       string
+	HTTPClient   *http.Client
+	MaxRetries   int
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	PageSize     int
+}
+
+func validateConfig(cfg Config) Config {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+
+	if cfg.RetryWaitMin == 0 {
+		cfg.RetryWaitMin = defaultRetryWaitMin
+	}
+
+	if cfg.RetryWaitMax == 0 {
+		cfg.RetryWaitMax = defaultRetryWaitMax
+	}
+
+	if cfg.PageSize == 0 {
+		cfg.PageSize = defaultPageSize
+	}
+
+	return cfg
+}
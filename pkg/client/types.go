@@ -0,0 +1,50 @@
+package client
+
+import "time"
+
+// Типы повторяют JSON API и не зависят от внутренних DTO сервера.
+
+type Task struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	CreatedBy   string `json:"created_by"`
+	Assignee    string `json:"assignee"`
+}
+
+type TaskList struct {
+	Tasks []Task `json:"tasks"`
+}
+
+// EventResync приходит первым событием потока, если Last-Event-ID уже нет в истории сервера.
+// Пропущенные события восстановить нельзя: задачи нужно перечитать. У события нет ID и задачи.
+const EventResync = "resync"
+
+type TaskEvent struct {
+	ID   string    `json:"id,omitempty"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Task *Task     `json:"task,omitempty"`
+}
+
+type CreateTaskRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Assignee    string `json:"assignee"`
+}
+
+type UpdateTaskRequest struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	// Assignee не меняется, если поле не передано
+	Assignee *string `json:"assignee,omitempty"`
+}
+
+// errorResponse тело ответа API с ошибкой.
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message,omitempty"`
+}
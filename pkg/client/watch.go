@@ -0,0 +1,129 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var errStreamClosed = errors.New("event stream closed by server")
+
+// Watch подписывается на поток событий GET /tasks/events. При обрыве соединения
+// клиент переподключается с Last-Event-ID последнего полученного события. Если сервер
+// его уже не помнит, поток начинается с события EventResync.
+// Итерация завершается при отмене ctx или после MaxRetries неудачных переподключений подряд.
+func (c *Client) Watch(ctx context.Context, opts WatchOptions) iter.Seq2[TaskEvent, error] {
+	return func(yield func(TaskEvent, error) bool) {
+		lastEventID := opts.LastEventID
+		stopped := false
+		failures := 0
+
+		for {
+			err := c.stream(ctx, opts, lastEventID, func(e TaskEvent) bool {
+				if e.ID != "" {
+					lastEventID = e.ID
+				}
+				failures = 0
+
+				if !yield(e, nil) {
+					stopped = true
+					return false
+				}
+
+				return true
+			})
+			if stopped || ctx.Err() != nil {
+				return
+			}
+
+			if !retryable(ctx, err) || failures >= c.cfg.MaxRetries {
+				yield(TaskEvent{}, err)
+				return
+			}
+
+			if err := sleep(ctx, c.backoff(failures, err)); err != nil {
+				return
+			}
+			failures++
+		}
+	}
+}
+
+func (c *Client) stream(ctx context.Context, opts WatchOptions, lastEventID string, emit func(TaskEvent) bool) error {
+	query := url.Values{}
+	for _, id := range opts.TaskIDs {
+		query.Add("id", strconv.Itoa(id))
+	}
+	if opts.Status != "" {
+		query.Set("status", opts.Status)
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		header.Set("Last-Event-ID", lastEventID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url("/tasks/events", query), nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header = header
+	c.setCredentials(req.Header)
+
+	// Таймаут основного клиента оборвал бы долгоживущий поток.
+	httpClient := *c.cfg.HTTPClient
+	httpClient.Timeout = 0
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	var data strings.Builder
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+
+			var e TaskEvent
+			if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+				return fmt.Errorf("failed to decode event: %w", err)
+			}
+			data.Reset()
+
+			if !emit(e) {
+				return nil
+			}
+
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return errStreamClosed
+}
@@ -24,3 +24,23 @@ func WithSignal(ctx context.Context, sig ...os.Signal) (context.Context, context
 
 	return ctx, cancel
 }
+
+// OnSignal вызывает fn на каждый из сигналов sig, пока ctx не отменен.
+func OnSignal(ctx context.Context, fn func(os.Signal), sig ...os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+
+	go func() {
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case s := <-ch:
+				fn(s)
+			}
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+// Package events описывает события задач, которые сервис публикует в Kafka. Событие — конверт
+// CloudEvents 1.0 в structured mode: значение сообщения содержит JSON с атрибутами и data,
+// ключ — ID задачи, поэтому события одной задачи попадают в одну партицию и читаются по порядку.
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SpecVersion = "1.0"
+	// ContentType заголовок content-type сообщения в structured mode.
+	ContentType     = "application/cloudevents+json"
+	DataContentType = "application/json"
+	Source          = "/task-service"
+	// DataSchema версия схемы data. Меняется при несовместимых изменениях Task, новые поля
+	// добавляются без смены версии.
+	DataSchema = "urn:task-service:task:v1"
+
+	TypeTaskCreated   = "task.created"
+	TypeTaskUpdated   = "task.updated"
+	TypeTaskCompleted = "task.completed"
+)
+
+// Заголовки Kafka. ce_type дублирует атрибут type, чтобы подписчики могли отбрасывать
+// ненужные события, не разбирая значение.
+const (
+	HeaderContentType = "content-type"
+	HeaderType        = "ce_type"
+)
+
+var ErrSpecVersion = errors.New("unsupported cloudevents specversion")
+
+// Task данные события — состояние задачи после изменения.
+type Task struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	CreatedBy   string `json:"created_by"`
+	Assignee    string `json:"assignee"`
+	TenantID    string `json:"tenant_id"`
+}
+
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Type            string    `json:"type"`
+	Source          string    `json:"source"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	DataSchema      string    `json:"dataschema"`
+	// TenantID расширение CloudEvents с тенантом задачи.
+	TenantID string `json:"tenantid,omitempty"`
+	// PreviousStatus расширение CloudEvents для task.updated и task.completed: статус задачи до изменения.
+	PreviousStatus string `json:"previousstatus,omitempty"`
+	Data           Task   `json:"data"`
+}
+
+// New создает событие typ о задаче task с новым id и текущим временем.
+func New(typ string, task Task) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.NewString(),
+		Type:            typ,
+		Source:          Source,
+		Subject:         strconv.Itoa(task.ID),
+		Time:            time.Now().UTC(),
+		DataContentType: DataContentType,
+		DataSchema:      DataSchema,
+		TenantID:        task.TenantID,
+		Data:            task,
+	}
+}
+
+// Key ключ сообщения Kafka — ID задачи.
+func (e Event) Key() []byte {
+	return []byte(e.Subject)
+}
+
+// Parse разбирает значение сообщения Kafka.
+func Parse(value []byte) (Event, error) {
+	var result Event
+
+	if err := json.Unmarshal(value, &result); err != nil {
+		return Event{}, fmt.Errorf("invalid event: %w", err)
+	}
+
+	if result.SpecVersion != SpecVersion {
+		return Event{}, fmt.Errorf("%w %q", ErrSpecVersion, result.SpecVersion)
+	}
+
+	return result, nil
+}
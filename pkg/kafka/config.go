@@ -1,6 +1,69 @@
 package kafka
 
+import "time"
+
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+
+	AcksAll    = "all"
+	AcksLeader = "leader"
+	AcksNone   = "none"
+
+	OffsetNewest = "newest"
+	OffsetOldest = "oldest"
+
+	defaultClientID = "task-service"
+	defaultGroupID  = "task-service"
+)
+
 type Config struct {
-	Broker string
-	Topic  string
+	Brokers []string
+	Topic   string
+	// ClientID имя клиента в логах и квотах брокера.
+	ClientID string
+	// GroupID группа потребителей ConsumeMessages: процессы одной группы делят партиции топика,
+	// а прочитанные смещения сохраняются в Kafka.
+	GroupID string
+	// InitialOffset откуда группа читает партицию без сохраненного смещения: newest (по умолчанию)
+	// или oldest.
+	InitialOffset string
+	// Version версия протокола Kafka, например "3.6.0". Пустая — версия sarama по умолчанию.
+	Version  string
+	SASL     SASLConfig
+	TLS      TLSConfig
+	Producer ProducerConfig
+}
+
+type SASLConfig struct {
+	// Mechanism PLAIN, SCRAM-SHA-256 или SCRAM-SHA-512. Пустой отключает SASL.
+	Mechanism string
+	User      string
+	Password  string
+}
+
+type TLSConfig struct {
+	Enabled bool
+	// CAFile сертификат CA брокеров. Пустой — системные сертификаты.
+	CAFile string
+	// CertFile и KeyFile клиентский сертификат для mTLS.
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
+
+type ProducerConfig struct {
+	// Acks подтверждение записи: all (по умолчанию), leader или none.
+	Acks string
+	// Retries сколько раз повторять отправку сообщения.
+	Retries int
+	// Compression none (по умолчанию), gzip, snappy, lz4 или zstd.
+	Compression string
+	// Idempotent исключает дубли при повторах; требует Acks all и Kafka 0.11+.
+	Idempotent bool
+	// Linger сколько ждать, набирая пачку; BatchBytes и BatchMessages отправляют пачку раньше.
+	Linger        time.Duration
+	BatchBytes    int
+	BatchMessages int
 }
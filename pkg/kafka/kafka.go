@@ -1,90 +1,304 @@
 package kafka
 
 import (
+	"TaskService/pkg/logger"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/codes"
+	"strconv"
+	"sync"
+	"sync/atomic"
 )
 
 type Kafka interface {
-	SendMessage(message []byte) error
-	ConsumeMessages(handler func(message *sarama.ConsumerMessage)) error
+	SendMessage(ctx context.Context, message Message) error
+	// ConsumeMessages читает топик в группе GroupID до отмены ctx, затем дожидается
+	// окончания уже начатых вызовов handler. Сообщения одной партиции передаются handler по очереди,
+	// смещение сообщения сохраняется после возврата из handler.
+	ConsumeMessages(ctx context.Context, handler func(message *sarama.ConsumerMessage)) error
+	// ConsumeAll читает все партиции топика с новых сообщений без группы, так что каждый процесс
+	// получает все сообщения, а смещения не сохраняются. Возвращается после отмены ctx.
+	ConsumeAll(ctx context.Context, handler func(message *sarama.ConsumerMessage)) error
+	// Ping запрашивает у брокеров метаданные топика.
+	Ping(ctx context.Context) error
+	// ConsumerAlive сообщает, что после ConsumeMessages процесс состоит в группе.
+	ConsumerAlive() bool
 	Close() error
 }
 
+// Message сообщение для отправки в топик клиента.
+type Message struct {
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
 type KafkaClient struct {
+	client   sarama.Client
 	producer sarama.SyncProducer
 	consumer sarama.Consumer
+	group    sarama.ConsumerGroup
 	topic    string
+
+	// member выставляется на время сессии группы: от распределения партиций до следующей ребалансировки
+	member atomic.Bool
 }
 
 func NewKafkaClient(cfg Config) (Kafka, error) {
-	producerConfig := sarama.NewConfig()
-	producerConfig.Producer.RequiredAcks = sarama.WaitForAll
-	producerConfig.Producer.Retry.Max = 5
-	producerConfig.Producer.Return.Successes = true
+	config, err := NewSaramaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	brokers := make([]string, 0)
-	brokers = append(brokers, cfg.Broker)
+	client, err := sarama.NewClient(cfg.Brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
 
-	producer, err := sarama.NewSyncProducer(brokers, producerConfig)
+	producer, err := sarama.NewSyncProducerFromClient(client)
 	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to create producer: %w", err)
 	}
 
-	consumerConfig := sarama.NewConfig()
-	consumer, err := sarama.NewConsumer(brokers, consumerConfig)
+	groupID := cfg.GroupID
+	if groupID == "" {
+		groupID = defaultGroupID
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
 	if err != nil {
 		producer.Close()
+		client.Close()
 		return nil, fmt.Errorf("failed to create consumer: %w", err)
 	}
 
+	group, err := sarama.NewConsumerGroupFromClient(groupID, client)
+	if err != nil {
+		consumer.Close()
+		producer.Close()
+		client.Close()
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
 	result := &KafkaClient{
+		client:   client,
 		producer: producer,
 		consumer: consumer,
+		group:    group,
 		topic:    cfg.Topic,
 	}
 
 	return result, nil
 }
 
-func (kc *KafkaClient) SendMessage(message []byte) error {
+func (kc *KafkaClient) SendMessage(ctx context.Context, message Message) error {
+	ctx, span := startProduce(ctx, kc.topic)
+	defer span.End()
+
 	msg := &sarama.ProducerMessage{
 		Topic: kc.topic,
-		Value: sarama.ByteEncoder(message),
+		Value: sarama.ByteEncoder(message.Value),
+	}
+
+	if message.Key != nil {
+		msg.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	for key, value := range inject(ctx, message.Headers) {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
 	}
 
 	partition, offset, err := kc.producer.SendMessage(msg)
 	if err != nil {
+		produceErrors.WithLabelValues(kc.topic).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
-	fmt.Printf("Message sent to topic %s, partition %d at offset %d\n", kc.topic, partition, offset)
+	producedMessages.WithLabelValues(kc.topic).Inc()
+
+	log := logger.WithName(logger.FromContext(ctx), "kafka")
+	log.Debug().Str("topic", kc.topic).Int32("partition", partition).Int64("offset", offset).Msg("message sent")
+
+	return nil
+}
+
+func (kc *KafkaClient) ConsumeMessages(ctx context.Context, handler func(message *sarama.ConsumerMessage)) error {
+	log := logger.WithName(logger.FromContext(ctx), "kafka")
+
+	// канал закрывается вместе с группой в Close
+	go func() {
+		for err := range kc.group.Errors() {
+			partition := ""
+
+			var consumerErr *sarama.ConsumerError
+			if errors.As(err, &consumerErr) {
+				partition = strconv.Itoa(int(consumerErr.Partition))
+			}
+
+			consumeErrors.WithLabelValues(kc.topic, partition).Inc()
+			log.Warn().Err(err).Msg("consumer group error")
+		}
+	}()
+
+	h := &groupHandler{kc: kc, handler: handler}
+
+	// Consume возвращается при каждой ребалансировке, после чего нужно войти в группу заново
+	for ctx.Err() == nil {
+		if err := kc.group.Consume(ctx, []string{kc.topic}, h); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to consume: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func (kc *KafkaClient) ConsumeMessages(handler func(message *sarama.ConsumerMessage)) error {
+// groupHandler передает сообщения назначенных партиций в handler и отмечает обработанные.
+type groupHandler struct {
+	kc      *KafkaClient
+	handler func(message *sarama.ConsumerMessage)
+}
+
+func (h *groupHandler) Setup(sarama.ConsumerGroupSession) error {
+	h.kc.member.Store(true)
+	return nil
+}
+
+func (h *groupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	h.kc.member.Store(false)
+	return nil
+}
+
+func (h *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	label := strconv.Itoa(int(claim.Partition()))
+
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok || session.Context().Err() != nil {
+				return nil
+			}
+
+			consumedMessages.WithLabelValues(h.kc.topic, label).Inc()
+			consumerLag.WithLabelValues(h.kc.topic, label).Set(float64(claim.HighWaterMarkOffset() - message.Offset - 1))
+
+			h.handler(message)
+			session.MarkMessage(message, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (kc *KafkaClient) ConsumeAll(ctx context.Context, handler func(message *sarama.ConsumerMessage)) error {
 	partitionList, err := kc.consumer.Partitions(kc.topic)
 	if err != nil {
 		return fmt.Errorf("failed to get partitions: %w", err)
 	}
 
+	var (
+		wg        sync.WaitGroup
+		consumers = make([]sarama.PartitionConsumer, 0, len(partitionList))
+	)
+
+	stop := func() {
+		for _, pc := range consumers {
+			pc.AsyncClose()
+		}
+		wg.Wait()
+	}
+
 	for _, partition := range partitionList {
 		pc, err := kc.consumer.ConsumePartition(kc.topic, partition, sarama.OffsetNewest)
 		if err != nil {
+			stop()
 			return fmt.Errorf("failed to consume partition: %w", err)
 		}
 
+		consumers = append(consumers, pc)
+
+		label := strconv.Itoa(int(partition))
+
+		wg.Add(2)
+
 		go func(pc sarama.PartitionConsumer) {
-			defer pc.Close()
+			defer wg.Done()
+			for range pc.Errors() {
+				consumeErrors.WithLabelValues(kc.topic, label).Inc()
+			}
+		}(pc)
+
+		go func(pc sarama.PartitionConsumer) {
+			defer wg.Done()
 			for message := range pc.Messages() {
+				// после остановки дочитываем буфер без обработки, чтобы AsyncClose завершился
+				if ctx.Err() != nil {
+					continue
+				}
+
 				handler(message)
 			}
 		}(pc)
 	}
 
+	<-ctx.Done()
+
+	stop()
+
 	return nil
 }
 
+// Header возвращает значение заголовка сообщения или пустую строку.
+func Header(message *sarama.ConsumerMessage, key string) string {
+	for _, h := range message.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+func (kc *KafkaClient) Ping(ctx context.Context) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- kc.client.RefreshMetadata(kc.topic)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to refresh metadata: %w", err)
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	partitions, err := kc.client.Partitions(kc.topic)
+	if err != nil {
+		return fmt.Errorf("failed to get partitions: %w", err)
+	}
+
+	if len(partitions) == 0 {
+		return errors.New("topic has no partitions")
+	}
+
+	return nil
+}
+
+func (kc *KafkaClient) ConsumerAlive() bool {
+	return kc.member.Load()
+}
+
 func (kc *KafkaClient) Close() error {
 	var errs []error
 
@@ -96,6 +310,15 @@ func (kc *KafkaClient) Close() error {
 		errs = append(errs, fmt.Errorf("failed to close consumer: %w", err))
 	}
 
+	// смещения обработанных сообщений сохраняются при закрытии группы
+	if err := kc.group.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close consumer group: %w", err))
+	}
+
+	if err := kc.client.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close client: %w", err))
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("errors closing kafka client: %v", errs)
 	}
@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	producedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_produced_messages_total",
+		Help: "Number of messages sent to Kafka.",
+	}, []string{"topic"})
+
+	produceErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_produce_errors_total",
+		Help: "Number of failed Kafka sends.",
+	}, []string{"topic"})
+
+	consumedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumed_messages_total",
+		Help: "Number of messages received from Kafka.",
+	}, []string{"topic", "partition"})
+
+	consumeErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consume_errors_total",
+		Help: "Number of Kafka consumer errors.",
+	}, []string{"topic", "partition"})
+
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "Messages in the partition not yet received by the consumer.",
+	}, []string{"topic", "partition"})
+)
@@ -0,0 +1,147 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/IBM/sarama"
+)
+
+// NewSaramaConfig переносит cfg на sarama.Config и проверяет сочетание настроек,
+// например, что идемпотентный producer включен вместе с acks all.
+func NewSaramaConfig(cfg Config) (*sarama.Config, error) {
+	config := sarama.NewConfig()
+
+	config.ClientID = cfg.ClientID
+	if config.ClientID == "" {
+		config.ClientID = defaultClientID
+	}
+
+	if cfg.Version != "" {
+		version, err := sarama.ParseKafkaVersion(cfg.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kafka version %q: %w", cfg.Version, err)
+		}
+		config.Version = version
+	}
+
+	switch cfg.Producer.Acks {
+	case "", AcksAll:
+		config.Producer.RequiredAcks = sarama.WaitForAll
+	case AcksLeader:
+		config.Producer.RequiredAcks = sarama.WaitForLocal
+	case AcksNone:
+		config.Producer.RequiredAcks = sarama.NoResponse
+	default:
+		return nil, fmt.Errorf("unknown producer acks %q", cfg.Producer.Acks)
+	}
+
+	switch cfg.Producer.Compression {
+	case "", "none":
+		config.Producer.Compression = sarama.CompressionNone
+	case "gzip":
+		config.Producer.Compression = sarama.CompressionGZIP
+	case "snappy":
+		config.Producer.Compression = sarama.CompressionSnappy
+	case "lz4":
+		config.Producer.Compression = sarama.CompressionLZ4
+	case "zstd":
+		config.Producer.Compression = sarama.CompressionZSTD
+	default:
+		return nil, fmt.Errorf("unknown producer compression %q", cfg.Producer.Compression)
+	}
+
+	config.Producer.Retry.Max = cfg.Producer.Retries
+	config.Producer.Idempotent = cfg.Producer.Idempotent
+	if cfg.Producer.Idempotent {
+		// иначе повтор запроса может обогнать предыдущий и нарушить порядок
+		config.Net.MaxOpenRequests = 1
+	}
+
+	config.Producer.Flush.Frequency = cfg.Producer.Linger
+	config.Producer.Flush.Bytes = cfg.Producer.BatchBytes
+	config.Producer.Flush.Messages = cfg.Producer.BatchMessages
+	config.Producer.Return.Successes = true
+	config.Consumer.Return.Errors = true
+
+	switch cfg.InitialOffset {
+	case "", OffsetNewest:
+		config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	case OffsetOldest:
+		config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	default:
+		return nil, fmt.Errorf("unknown initial offset %q", cfg.InitialOffset)
+	}
+
+	if cfg.SASL.Mechanism != "" {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.Handshake = true
+		config.Net.SASL.User = cfg.SASL.User
+		config.Net.SASL.Password = cfg.SASL.Password
+
+		switch cfg.SASL.Mechanism {
+		case SASLPlain:
+			config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		case SASLScramSHA256:
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+			config.Net.SASL.SCRAMClientGeneratorFunc = newSCRAMClient(cfg.SASL.Mechanism)
+		case SASLScramSHA512:
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+			config.Net.SASL.SCRAMClientGeneratorFunc = newSCRAMClient(cfg.SASL.Mechanism)
+		default:
+			return nil, fmt.Errorf("unknown sasl mechanism %q", cfg.SASL.Mechanism)
+		}
+	}
+
+	if cfg.TLS.Enabled {
+		tlsConfig, err := newTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = tlsConfig
+	}
+
+	// ошибка уже содержит префикс "kafka: invalid configuration"
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func newTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	result := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// только для тестовых стендов с самоподписанными сертификатами
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CAFile != "" {
+		data, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kafka CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+
+		result.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kafka client certificate: %w", err)
+		}
+
+		result.Certificates = []tls.Certificate{cert}
+	}
+
+	return result, nil
+}
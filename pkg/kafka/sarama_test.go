@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSaramaConfig(t *testing.T) {
+	cfg := Config{
+		Brokers:       []string{"kafka-1:9093", "kafka-2:9093"},
+		Topic:         "tasks",
+		ClientID:      "task-service-test",
+		Version:       "3.6.0",
+		InitialOffset: OffsetOldest,
+		SASL:          SASLConfig{Mechanism: SASLScramSHA512, User: "svc", Password: "secret"},
+		TLS:           TLSConfig{Enabled: true},
+		Producer: ProducerConfig{
+			Retries:       3,
+			Compression:   "zstd",
+			Idempotent:    true,
+			Linger:        5 * time.Millisecond,
+			BatchMessages: 100,
+		},
+	}
+
+	config, err := NewSaramaConfig(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "task-service-test", config.ClientID)
+	assert.Equal(t, sarama.V3_6_0_0, config.Version)
+	assert.True(t, config.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512), config.Net.SASL.Mechanism)
+	assert.NotNil(t, config.Net.SASL.SCRAMClientGeneratorFunc)
+	assert.True(t, config.Net.TLS.Enable)
+	assert.Equal(t, sarama.WaitForAll, config.Producer.RequiredAcks)
+	assert.Equal(t, sarama.CompressionZSTD, config.Producer.Compression)
+	assert.Equal(t, 1, config.Net.MaxOpenRequests)
+	assert.Equal(t, sarama.OffsetOldest, config.Consumer.Offsets.Initial)
+	assert.Equal(t, 3, config.Producer.Retry.Max)
+	assert.Equal(t, 5*time.Millisecond, config.Producer.Flush.Frequency)
+	assert.Equal(t, 100, config.Producer.Flush.Messages)
+}
+
+func TestNewSaramaConfig_Invalid(t *testing.T) {
+	_, err := NewSaramaConfig(Config{Producer: ProducerConfig{Acks: AcksLeader, Idempotent: true, Retries: 1}})
+	assert.ErrorContains(t, err, "Idempotent producer requires Producer.RequiredAcks to be WaitForAll")
+
+	_, err = NewSaramaConfig(Config{SASL: SASLConfig{Mechanism: "GSSAPI"}})
+	assert.EqualError(t, err, `unknown sasl mechanism "GSSAPI"`)
+
+	_, err = NewSaramaConfig(Config{Version: "latest"})
+	assert.ErrorContains(t, err, `invalid kafka version "latest"`)
+
+	_, err = NewSaramaConfig(Config{InitialOffset: "earliest"})
+	assert.EqualError(t, err, `unknown initial offset "earliest"`)
+}
@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+)
+
+// scramClient реализует sarama.SCRAMClient поверх xdg-go/scram.
+type scramClient struct {
+	hash scram.HashGeneratorFcn
+	conv *scram.ClientConversation
+}
+
+func newSCRAMClient(mechanism string) func() sarama.SCRAMClient {
+	hash := scram.HashGeneratorFcn(sha256.New)
+	if mechanism == SASLScramSHA512 {
+		hash = sha512.New
+	}
+
+	return func() sarama.SCRAMClient {
+		return &scramClient{hash: hash}
+	}
+}
+
+func (c *scramClient) Begin(user, password, authzID string) error {
+	client, err := c.hash.NewClient(user, password, authzID)
+	if err != nil {
+		return err
+	}
+
+	c.conv = client.NewConversation()
+
+	return nil
+}
+
+func (c *scramClient) Step(challenge string) (string, error) {
+	return c.conv.Step(challenge)
+}
+
+func (c *scramClient) Done() bool {
+	return c.conv.Done()
+}
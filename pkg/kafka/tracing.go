@@ -0,0 +1,48 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("TaskService/pkg/kafka")
+
+// inject добавляет в заголовки сообщения контекст трассировки из ctx.
+func inject(ctx context.Context, headers map[string]string) map[string]string {
+	result := make(map[string]string, len(headers)+2)
+	for k, v := range headers {
+		result[k] = v
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(result))
+
+	return result
+}
+
+// Extract возвращает контекст с трассировкой из заголовков сообщения.
+func Extract(ctx context.Context, message *sarama.ConsumerMessage) context.Context {
+	carrier := propagation.MapCarrier{}
+	for _, h := range message.Headers {
+		if h != nil {
+			carrier[string(h.Key)] = string(h.Value)
+		}
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+func startProduce(ctx context.Context, topic string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationTypePublish,
+		),
+	)
+}
@@ -10,6 +10,9 @@ const (
 	defaultDir         = "/app/data/logs"
 	defaultFilename    = "app.log"
 	defaultServiceName = "main"
+	defaultEnvironment = "development"
+	defaultVersion     = "dev"
+	defaultSamplePer   = time.Second
 	defaultTimeFormat  = time.RFC3339
 	defaultMaxSizeMB   = 50
 	defaultMaxBackups  = 10
@@ -36,6 +39,20 @@ type Config struct {
 	DuplicateToStdout bool
 	TimeFormat        string
 	ServiceName       string
+	// Environment окружение: production, staging, development.
+	Environment string
+	// Version и Commit версия сборки, см. pkg/version.
+	Version string
+	Commit  string
+	// NamedLevels уровни отдельных логгеров Named, например {"kafka": "DEBUG"}.
+	NamedLevels map[string]string
+	// Redact поля записей, значения которых заменяются: {"title": "hash", "description": "mask"}.
+	Redact map[string]string
+	// Выборка для логгеров Sampled: первые SampleBurst записей за SamplePeriod,
+	// затем каждая SampleEvery-я. SampleEvery <= 1 выключает выборку.
+	SampleBurst  uint32
+	SamplePeriod time.Duration
+	SampleEvery  uint32
 }
 
 func validateConfig(cfg Config) Config {
@@ -63,6 +80,18 @@ func validateConfig(cfg Config) Config {
 		cfg.ServiceName = defaultServiceName
 	}
 
+	if cfg.Environment == "" {
+		cfg.Environment = defaultEnvironment
+	}
+
+	if cfg.Version == "" {
+		cfg.Version = defaultVersion
+	}
+
+	if cfg.SamplePeriod <= 0 {
+		cfg.SamplePeriod = defaultSamplePer
+	}
+
 	return cfg
 }
 
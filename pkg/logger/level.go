@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	level atomic.Int32
+
+	namedMu     sync.RWMutex
+	namedLevels = make(map[string]zerolog.Level)
+)
+
+func init() {
+	level.Store(int32(zerolog.DebugLevel))
+}
+
+// levelFilter отсекает записи ниже текущего уровня логгера name (или общего уровня).
+// Реализован через zerolog.Sampler, потому что уровень самого zerolog.Logger нельзя поменять
+// у уже созданных дочерних логгеров.
+// next дополнительно прореживает записи ниже WARNING, см. Sampled.
+type levelFilter struct {
+	name string
+	next zerolog.Sampler
+}
+
+func (f levelFilter) Sample(lvl zerolog.Level) bool {
+	if lvl < effectiveLevel(f.name) {
+		return false
+	}
+
+	if f.next != nil && lvl < zerolog.WarnLevel {
+		return f.next.Sample(lvl)
+	}
+
+	return true
+}
+
+func effectiveLevel(name string) zerolog.Level {
+	if name != "" {
+		namedMu.RLock()
+		lvl, ok := namedLevels[name]
+		namedMu.RUnlock()
+
+		if ok {
+			return lvl
+		}
+	}
+
+	return zerolog.Level(level.Load())
+}
+
+// ParseLevel разбирает имя уровня (DEBUG, INFO, WARNING, ERROR, FATAL, PANIC) без учета регистра.
+func ParseLevel(s string) (zerolog.Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case LevelDebug:
+		return zerolog.DebugLevel, nil
+	case LevelInfo:
+		return zerolog.InfoLevel, nil
+	case LevelWarn, "WARN":
+		return zerolog.WarnLevel, nil
+	case LevelError:
+		return zerolog.ErrorLevel, nil
+	case LevelFatal:
+		return zerolog.FatalLevel, nil
+	case LevelPanic:
+		return zerolog.PanicLevel, nil
+	default:
+		return zerolog.NoLevel, fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+func levelName(lvl zerolog.Level) string {
+	switch lvl {
+	case zerolog.DebugLevel:
+		return LevelDebug
+	case zerolog.InfoLevel:
+		return LevelInfo
+	case zerolog.WarnLevel:
+		return LevelWarn
+	case zerolog.ErrorLevel:
+		return LevelError
+	case zerolog.FatalLevel:
+		return LevelFatal
+	case zerolog.PanicLevel:
+		return LevelPanic
+	default:
+		return lvl.String()
+	}
+}
+
+// Level возвращает текущий общий уровень логирования.
+func Level() string {
+	return levelName(zerolog.Level(level.Load()))
+}
+
+// SetLevel меняет общий уровень логирования всех логгеров, включая уже созданные.
+func SetLevel(s string) error {
+	lvl, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+
+	level.Store(int32(lvl))
+
+	return nil
+}
+
+// NamedLevels возвращает уровни, переопределенные для логгеров Named и WithName.
+func NamedLevels() map[string]string {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+
+	result := make(map[string]string, len(namedLevels))
+	for name, lvl := range namedLevels {
+		result[name] = levelName(lvl)
+	}
+
+	return result
+}
+
+// SetNamedLevels заменяет все переопределения уровней. При ошибке в любом уровне ничего не меняется.
+func SetNamedLevels(levels map[string]string) error {
+	parsed := make(map[string]zerolog.Level, len(levels))
+
+	for name, s := range levels {
+		lvl, err := ParseLevel(s)
+		if err != nil {
+			return fmt.Errorf("logger %q: %w", name, err)
+		}
+		parsed[name] = lvl
+	}
+
+	namedMu.Lock()
+	namedLevels = parsed
+	namedMu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLevel_Runtime(t *testing.T) {
+	t.Cleanup(func() {
+		SetLevel(LevelDebug)
+		SetNamedLevels(nil)
+	})
+
+	var buf bytes.Buffer
+
+	base := newLogger(&buf, validateConfig(Config{}))
+	child := base.With().Str("request_id", "1").Logger()
+	kafka := WithName(child, "kafka")
+
+	require.NoError(t, SetLevel(LevelWarn))
+
+	child.Info().Msg("hidden")
+	kafka.Info().Msg("hidden")
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, SetNamedLevels(map[string]string{"kafka": "debug"}))
+
+	child.Debug().Msg("hidden")
+	kafka.Debug().Msg("kafka debug")
+	assert.Contains(t, buf.String(), "kafka debug")
+	assert.NotContains(t, buf.String(), "hidden")
+
+	assert.Equal(t, LevelWarn, Level())
+	assert.Equal(t, map[string]string{"kafka": LevelDebug}, NamedLevels())
+}
+
+func TestSetLevel_Invalid(t *testing.T) {
+	t.Cleanup(func() {
+		SetLevel(LevelDebug)
+		SetNamedLevels(nil)
+	})
+
+	require.NoError(t, SetNamedLevels(map[string]string{"kafka": LevelInfo}))
+
+	assert.Error(t, SetLevel("verbose"))
+	assert.Error(t, SetNamedLevels(map[string]string{"postgres": LevelWarn, "kafka": "verbose"}))
+
+	assert.Equal(t, LevelDebug, Level())
+	assert.Equal(t, map[string]string{"kafka": LevelInfo}, NamedLevels())
+}
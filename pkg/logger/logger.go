@@ -30,25 +30,36 @@ func Get() zerolog.Logger {
 	return *baseLogger
 }
 
+// Named возвращает базовый логгер компонента name. Его уровень можно переопределить через SetNamedLevels.
 func Named(name string) zerolog.Logger {
 	if baseLogger == nil {
 		panic(errNilBaseLogger)
 	}
 
-	return Get().With().Str("name", name).Logger()
+	return WithName(Get(), name)
+}
+
+// WithName делает из логгера (например, логгера запроса) логгер компонента name.
+func WithName(logger zerolog.Logger, name string) zerolog.Logger {
+	return logger.With().Str("name", name).Logger().Sample(levelFilter{name: name})
 }
 
 func WrapToContext(ctx context.Context, logger zerolog.Logger) context.Context {
 	return context.WithValue(ctx, txContextKey{}, logger)
 }
 
+// FromContext возвращает логгер запроса. Без него в контексте — базовый логгер,
+// а если Init еще не вызывался — логгер, который ничего не пишет.
 func FromContext(ctx context.Context) zerolog.Logger {
-	logger, ok := ctx.Value(txContextKey{}).(zerolog.Logger)
-	if !ok {
-		panic(errNilBaseLogger)
+	if logger, ok := ctx.Value(txContextKey{}).(zerolog.Logger); ok {
+		return logger
 	}
 
-	return logger
+	if baseLogger == nil {
+		return zerolog.Nop()
+	}
+
+	return *baseLogger
 }
 
 func Init(cfg Config) error {
@@ -81,14 +92,56 @@ func Init(cfg Config) error {
 	}
 
 	zerolog.TimeFieldFormat = cfg.TimeFormat
+	zerolog.CallerMarshalFunc = shortCaller
+
+	if len(cfg.Redact) > 0 {
+		redacted, err := newRedactWriter(w, cfg.Redact)
+		if err != nil {
+			return err
+		}
+		w = redacted
+	}
+
+	if s := newSampler(cfg); s != nil {
+		sampler.Store(&s)
+	} else {
+		sampler.Store(nil)
+	}
+
+	level.Store(int32(parseLogLevel(cfg.Level)))
 
+	if err := SetNamedLevels(cfg.NamedLevels); err != nil {
+		return err
+	}
+
+	logger := newLogger(w, cfg)
+
+	baseLogger = &logger
+
+	return nil
+}
+
+// newLogger создает логгер, который добавляет к каждой записи сервис, версию сборки, хост и окружение.
+func newLogger(w io.Writer, cfg Config) zerolog.Logger {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	// уровень проверяет levelFilter, чтобы его можно было менять без перезапуска
 	logger := zerolog.New(w).
-		Level(parseLogLevel(cfg.Level)).
+		Level(zerolog.TraceLevel).
+		Sample(levelFilter{}).
+		Hook(stackHook{}).
 		With().
 		Timestamp().
+		Caller().
+		Str("service", cfg.ServiceName).
+		Str("version", cfg.Version).
+		Str("commit", cfg.Commit).
+		Str("host", hostname).
+		Str("env", cfg.Environment).
 		Logger()
 
-	baseLogger = &logger
-
-	return nil
+	return logger
 }
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger_Metadata(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := newLogger(&buf, validateConfig(Config{
+		Level:       LevelInfo,
+		ServiceName: "task-service",
+		Environment: "production",
+		Version:     "v1.2.0",
+		Commit:      "abc123",
+	}))
+
+	log.Info().Msg("started")
+	log.Error().Err(errors.New("boom")).Msg("failed")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var info, failed map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &info))
+	require.NoError(t, json.Unmarshal(lines[1], &failed))
+
+	assert.Equal(t, "task-service", info["service"])
+	assert.Equal(t, "production", info["env"])
+	assert.Equal(t, "v1.2.0", info["version"])
+	assert.Equal(t, "abc123", info["commit"])
+	assert.NotEmpty(t, info["host"])
+	assert.Contains(t, info["caller"], "logger/logger_test.go:")
+	assert.NotContains(t, info, "stack")
+
+	stack, ok := failed["stack"].([]interface{})
+	require.True(t, ok)
+	require.NotEmpty(t, stack)
+	assert.Contains(t, stack[0], "TestNewLogger_Metadata")
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	RedactMask = "mask"
+	RedactHash = "hash"
+
+	maskedValue = "***"
+)
+
+// redactWriter заменяет значения полей записи до того, как она попадет в вывод.
+// zerolog пишет поля сразу в буфер события, поэтому хук не может их изменить, и замена
+// выполняется над готовой JSON-записью. Обрабатываются только поля верхнего уровня.
+type redactWriter struct {
+	next   io.Writer
+	fields map[string]string
+	// needles `"field":` для быстрой проверки, есть ли в записи поля для замены
+	needles [][]byte
+}
+
+func newRedactWriter(next io.Writer, fields map[string]string) (io.Writer, error) {
+	result := &redactWriter{
+		next:   next,
+		fields: make(map[string]string, len(fields)),
+	}
+
+	for field, mode := range fields {
+		if mode == "" {
+			mode = RedactMask
+		}
+
+		if mode != RedactMask && mode != RedactHash {
+			return nil, fmt.Errorf("field %q: unknown redaction mode %q", field, mode)
+		}
+
+		result.fields[field] = mode
+		result.needles = append(result.needles, []byte(`"`+field+`":`))
+	}
+
+	return result, nil
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	if !w.matches(p) {
+		return w.next.Write(p)
+	}
+
+	out, err := w.redact(p)
+	if err != nil {
+		// запись не разобралась как JSON: пишем только признак, чтобы не пропустить данные
+		out = []byte(`{"level":"error","message":"log record redaction failed"}` + "\n")
+	}
+
+	if _, err := w.next.Write(out); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *redactWriter) matches(p []byte) bool {
+	for _, needle := range w.needles {
+		if bytes.Contains(p, needle) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redact переписывает запись, сохраняя порядок полей.
+func (w *redactWriter) redact(p []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("log record is not a JSON object")
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(p))
+	buf.WriteByte('{')
+
+	for i := 0; dec.More(); i++ {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if mode, ok := w.fields[key]; ok {
+			value = redactValue(value, mode)
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+func redactValue(value json.RawMessage, mode string) json.RawMessage {
+	if mode == RedactHash {
+		// по хешу можно сопоставить записи с одинаковым значением, не раскрывая его
+		sum := sha256.Sum256(value)
+		return json.RawMessage(`"sha256:` + hex.EncodeToString(sum[:8]) + `"`)
+	}
+
+	return json.RawMessage(`"` + maskedValue + `"`)
+}
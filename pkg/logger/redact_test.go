@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := newRedactWriter(&buf, map[string]string{"title": RedactHash, "description": ""})
+	require.NoError(t, err)
+
+	log := zerolog.New(w)
+
+	log.Info().Str("title", "Salary review").Str("description", "John Doe").Int("id", 1).Msg("process task")
+	log.Info().Str("title", "Salary review").Msg("again")
+	log.Info().Int("id", 2).Msg("untouched")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &first))
+	require.NoError(t, json.Unmarshal(lines[1], &second))
+
+	assert.Equal(t, maskedValue, first["description"])
+	assert.Regexp(t, "^sha256:[0-9a-f]{16}$", first["title"])
+	assert.Equal(t, first["title"], second["title"])
+	assert.Equal(t, float64(1), first["id"])
+	assert.NotContains(t, buf.String(), "Salary")
+	assert.NotContains(t, buf.String(), "John")
+	assert.Equal(t, `{"level":"info","id":2,"message":"untouched"}`, string(lines[2]))
+}
+
+func TestRedactWriter_UnknownMode(t *testing.T) {
+	_, err := newRedactWriter(&bytes.Buffer{}, map[string]string{"title": "drop"})
+	assert.Error(t, err)
+}
+
+func TestSampled(t *testing.T) {
+	t.Cleanup(func() { sampler.Store(nil) })
+
+	s := newSampler(validateConfig(Config{SampleBurst: 2, SamplePeriod: time.Hour, SampleEvery: 3}))
+	sampler.Store(&s)
+
+	var buf bytes.Buffer
+
+	log := Sampled(zerolog.New(&buf))
+
+	for i := 0; i < 8; i++ {
+		log.Info().Int("i", i).Msg("message")
+	}
+	log.Error().Msg("error")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+
+	// 2 записи пачки, затем каждая третья из оставшихся 6, ошибка всегда
+	assert.Len(t, lines, 5)
+	assert.Contains(t, string(lines[len(lines)-1]), `"level":"error"`)
+}
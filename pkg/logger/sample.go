@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+var sampler atomic.Pointer[zerolog.Sampler]
+
+// newSampler пропускает первые Burst записей за SamplePeriod, затем каждую SampleEvery-ю.
+// nil — выборка выключена.
+func newSampler(cfg Config) zerolog.Sampler {
+	if cfg.SampleEvery <= 1 {
+		return nil
+	}
+
+	every := &zerolog.BasicSampler{N: cfg.SampleEvery}
+
+	if cfg.SampleBurst == 0 {
+		return every
+	}
+
+	return &zerolog.BurstSampler{
+		Burst:       cfg.SampleBurst,
+		Period:      cfg.SamplePeriod,
+		NextSampler: every,
+	}
+}
+
+// Sampled возвращает логгер для частых записей (на каждое сообщение, запрос).
+// Записи уровня WARNING и выше не отбрасываются. Уровень такого логгера — общий, без Named.
+func Sampled(logger zerolog.Logger) zerolog.Logger {
+	s := sampler.Load()
+	if s == nil {
+		return logger
+	}
+
+	return logger.Sample(levelFilter{next: *s})
+}
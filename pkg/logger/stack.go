@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+const maxStackDepth = 32
+
+// stackHook добавляет стек вызова к записям уровня error и выше.
+type stackHook struct{}
+
+func (stackHook) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	if level < zerolog.ErrorLevel || level == zerolog.NoLevel || level == zerolog.Disabled {
+		return
+	}
+
+	e.Strs("stack", stack())
+}
+
+// stack возвращает кадры вызова в виде "функция file.go:line", начиная с кода, вызвавшего логгер.
+func stack() []string {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+
+	result := make([]string, 0, n)
+
+	// верхние кадры принадлежат zerolog и хуку
+	inLogger := true
+
+	for {
+		frame, more := frames.Next()
+
+		if inLogger && !isLoggerFrame(frame.Function) {
+			inLogger = false
+		}
+
+		if !inLogger && frame.Function != "" {
+			result = append(result, frame.Function+" "+shortPath(frame.File)+":"+strconv.Itoa(frame.Line))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return result
+}
+
+func isLoggerFrame(function string) bool {
+	return strings.HasPrefix(function, "github.com/rs/zerolog") ||
+		strings.HasPrefix(function, "TaskService/pkg/logger.stackHook")
+}
+
+// shortCaller оставляет в caller только каталог пакета и имя файла.
+func shortCaller(_ uintptr, file string, line int) string {
+	return shortPath(file) + ":" + strconv.Itoa(line)
+}
+
+func shortPath(file string) string {
+	return filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file))
+}
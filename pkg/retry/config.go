@@ -0,0 +1,47 @@
+package retry
+
+import "time"
+
+const (
+	defaultInitialInterval = 500 * time.Millisecond
+	defaultMaxInterval     = 10 * time.Second
+	defaultMultiplier      = 2
+	defaultTimeout         = 2 * time.Minute
+)
+
+type Config struct {
+	// InitialInterval пауза после первой неудачной попытки
+	InitialInterval time.Duration
+	// MaxInterval верхняя граница паузы между попытками
+	MaxInterval time.Duration
+	// Multiplier во сколько раз растет пауза после каждой попытки
+	Multiplier float64
+	// Timeout ограничивает все попытки вместе с паузами
+	Timeout time.Duration
+	// MaxAttempts наибольшее число попыток, 0 — без ограничения
+	MaxAttempts int
+}
+
+func validateConfig(cfg Config) Config {
+	if cfg.InitialInterval <= 0 {
+		cfg.InitialInterval = defaultInitialInterval
+	}
+
+	if cfg.MaxInterval <= 0 {
+		cfg.MaxInterval = defaultMaxInterval
+	}
+
+	if cfg.MaxInterval < cfg.InitialInterval {
+		cfg.MaxInterval = cfg.InitialInterval
+	}
+
+	if cfg.Multiplier < 1 {
+		cfg.Multiplier = defaultMultiplier
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return cfg
+}
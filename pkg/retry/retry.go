@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"TaskService/pkg/logger"
+)
+
+// Permanent помечает ошибку, которую повторять бессмысленно: Do сразу ее возвращает.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Do вызывает fn, пока она не завершится успешно, с экспоненциально растущей паузой между попытками.
+// Если за cfg.Timeout, cfg.MaxAttempts попыток или до отмены ctx успеха нет, возвращает последнюю ошибку fn.
+func Do(ctx context.Context, name string, cfg Config, fn func(ctx context.Context) error) error {
+	cfg = validateConfig(cfg)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	log := logger.WithName(logger.FromContext(ctx), "retry")
+
+	delay := cfg.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if attempt > 1 {
+				log.Info().Str("operation", name).Int("attempt", attempt).Msg("operation succeeded after retries")
+			}
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if cfg.MaxAttempts > 0 && attempt >= cfg.MaxAttempts {
+			return fmt.Errorf("%s: gave up after %d attempts: %w", name, attempt, err)
+		}
+
+		wait := jitter(delay)
+
+		log.Warn().Err(err).
+			Str("operation", name).
+			Int("attempt", attempt).
+			Dur("retry_in", wait).
+			Msg("operation failed, retrying")
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: gave up after %d attempts: %w", name, attempt, err)
+
+		case <-timer.C:
+		}
+
+		delay = min(time.Duration(float64(delay)*cfg.Multiplier), cfg.MaxInterval)
+	}
+}
+
+// jitter разносит попытки нескольких реплик во времени: пауза случайна в пределах ±20%.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration((rand.Float64()*0.4-0.2)*float64(d))
+}
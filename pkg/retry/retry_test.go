@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	cfg := Config{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, Timeout: time.Second}
+
+	attempts := 0
+
+	err := Do(context.Background(), "postgres", cfg, func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDo_Timeout(t *testing.T) {
+	cfg := Config{InitialInterval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond}
+
+	err := Do(context.Background(), "kafka", cfg, func(context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	assert.ErrorContains(t, err, "kafka: gave up after")
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestDo_MaxAttempts(t *testing.T) {
+	cfg := Config{InitialInterval: time.Millisecond, MaxAttempts: 2}
+
+	attempts := 0
+
+	err := Do(context.Background(), "update task", cfg, func(context.Context) error {
+		attempts++
+		return errors.New("connection reset")
+	})
+
+	assert.EqualError(t, err, "update task: gave up after 2 attempts: connection reset")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestDo_Permanent(t *testing.T) {
+	attempts := 0
+
+	err := Do(context.Background(), "get task", Config{}, func(context.Context) error {
+		attempts++
+		return Permanent(sql.ErrNoRows)
+	})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Equal(t, 1, attempts)
+}
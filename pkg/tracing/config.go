@@ -0,0 +1,28 @@
+package tracing
+
+const (
+	defaultServiceName = "task-service"
+	defaultSampleRatio = 1
+)
+
+type Config struct {
+	Enabled bool
+	// Endpoint URL OTLP/HTTP коллектора, например http://otel-collector:4318.
+	// Пустой — спаны пишутся в stdout.
+	Endpoint    string
+	ServiceName string
+	// SampleRatio доля трассируемых запросов от 0 до 1, если вызывающий не передал решение.
+	SampleRatio float64
+}
+
+func validateConfig(cfg Config) Config {
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = defaultServiceName
+	}
+
+	if cfg.SampleRatio <= 0 || cfg.SampleRatio > 1 {
+		cfg.SampleRatio = defaultSampleRatio
+	}
+
+	return cfg
+}
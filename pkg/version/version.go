@@ -0,0 +1,31 @@
+// Package version хранит версию сборки. Значения задаются при сборке:
+//
+//	go build -ldflags "-X TaskService/pkg/version.Version=v1.2.0 -X TaskService/pkg/version.Commit=$(git rev-parse --short HEAD)"
+package version
+
+import "runtime/debug"
+
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+func init() {
+	if Commit != "" {
+		return
+	}
+
+	// без ldflags берем ревизию, которую go build записывает из git
+	Commit = "unknown"
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && s.Value != "" {
+			Commit = s.Value
+		}
+	}
+}